@@ -82,3 +82,53 @@ func TestLogger_Info_Positive(t *testing.T) {
 
 	logger.Info("rate", "15", "low", 16, "high", 123.2, msg)
 }
+
+func BenchmarkGookitSlogBuilder_JSON(b *testing.B) {
+	h := handler.NewIOWriter(io.Discard, slog.NormalLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	logger := slog.NewWithHandlers(h)
+	logger.ReportCaller = false
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Build(slog.InfoLevel).
+			Str("rate", "15").
+			Int("low", 16).
+			Float("high", 123.2).
+			Msg(msg)
+	}
+}
+
+func BenchmarkGookitSlogFields_JSON(b *testing.B) {
+	h := handler.NewIOWriter(io.Discard, slog.NormalLevels)
+	h.SetFormatter(slog.NewJSONFormatter())
+	logger := slog.NewWithHandlers(h)
+	logger.ReportCaller = false
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.WithFields(slog.M{"rate": "15", "low": 16, "high": 123.2}).Info(msg)
+	}
+}
+
+func BenchmarkGookitSlogBuilder_Text(b *testing.B) {
+	logger := slog.NewWithHandlers(
+		handler.NewIOWriter(io.Discard, slog.NormalLevels),
+	)
+	logger.ReportCaller = false
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		logger.Build(slog.InfoLevel).
+			Str("rate", "15").
+			Int("low", 16).
+			Float("high", 123.2).
+			Msg(msg)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gookit/goutil/envutil"
@@ -63,6 +64,62 @@ func (l *Level) UnmarshalJSON(data []byte) error {
 	return err
 }
 
+// LevelVar a Level variable, it can be safely changed at runtime and shared by multiple handlers.
+//
+// The zero value is PanicLevel. Usage:
+//
+//	lv := slog.NewLevelVar(slog.InfoLevel)
+//	h1.SetLevelVar(lv)
+//	h2.SetLevelVar(lv)
+//	// change the level of h1, h2 at runtime
+//	lv.Set(slog.DebugLevel)
+type LevelVar struct {
+	val atomic.Uint32
+}
+
+// NewLevelVar create new LevelVar with init level
+func NewLevelVar(level Level) *LevelVar {
+	lv := &LevelVar{}
+	lv.Set(level)
+	return lv
+}
+
+// Level get current level
+func (v *LevelVar) Level() Level {
+	if lv := Level(v.val.Load()); lv > 0 {
+		return lv
+	}
+	return PanicLevel
+}
+
+// Set level value
+func (v *LevelVar) Set(level Level) { v.val.Store(uint32(level)) }
+
+// SetByName set level by level name. eg: info, debug
+func (v *LevelVar) SetByName(name string) { v.Set(LevelByName(name)) }
+
+// ShouldHandling compare level, if current level <= v.Level(), it will be record.
+func (v *LevelVar) ShouldHandling(curLevel Level) bool {
+	return v.Level().ShouldHandling(curLevel)
+}
+
+// String get level name
+func (v *LevelVar) String() string { return v.Level().String() }
+
+// MarshalJSON implement the JSON Marshal interface [encoding/json.Marshaler]
+func (v *LevelVar) MarshalJSON() ([]byte, error) { return v.Level().MarshalJSON() }
+
+// UnmarshalJSON implement the JSON Unmarshal interface [encoding/json.Unmarshaler]
+func (v *LevelVar) UnmarshalJSON(data []byte) error {
+	var lv Level
+	if err := lv.UnmarshalJSON(data); err != nil {
+		return err
+	}
+
+	v.Set(lv)
+	return nil
+}
+
 // Levels level list
 type Levels []Level
 
@@ -111,29 +168,43 @@ type M map[string]any
 // String map to string
 func (m M) String() string { return mapToString(m) }
 
-// DataValue for quick add data field to log Record.Data TODO
+// DataValue for quick add data field to log Record.Data
 //
 // Usage: slog.Info("some message", slog.NewValue("ip", "ip value"))
-//
 type DataValue struct {
 	Key string
 	Val any
 }
 
-// LogField for quick add new field to log Record.Fields TODO
+// NewValue create a DataValue, it will be added to Record.Data on log.
+func NewValue(key string, val any) DataValue {
+	return DataValue{Key: key, Val: val}
+}
+
+// LogField for quick add new field to log Record.Fields
 //
 // Usage: slog.Info("some message", slog.NewField("ip", "ip value"))
 //
+// NOTE: on the printf style methods, only the args after the verbs are extracted.
+// eg: slog.Infof("user %s", name, slog.NewField("ip", "ip value"))
 type LogField struct {
 	Key string
 	Val any
 }
 
+// NewField create a LogField, it will be added to Record.Fields on log.
+func NewField(key string, val any) LogField {
+	return LogField{Key: key, Val: val}
+}
+
 // ClockFn func
 type ClockFn func() time.Time
 
-// Now implements the Clocker
+// Now implements the Clocker. returns time.Now() if fn is nil.
 func (fn ClockFn) Now() time.Time {
+	if fn == nil {
+		return time.Now()
+	}
 	return fn()
 }
 
@@ -193,11 +264,27 @@ var (
 	//
 	// NOTICE: you must set `Logger.ReportCaller=true` for reporting caller
 	FieldKeyCaller = "caller"
+	// FieldKeyStacktrace the field key name for the call stack. see Logger.StackLevel
+	FieldKeyStacktrace = "stacktrace"
 
 	// FieldKeyLevel name
 	FieldKeyLevel = "level"
 	// FieldKeyError Define the key when adding errors using WithError.
 	FieldKeyError = "error"
+	// FieldKeyBadKey the key for collect the bad key-values of the "w" methods. eg: Infow
+	FieldKeyBadKey = "!BADKEY"
+	// FieldKeyTraceID the key for the trace id. see TraceProcessor
+	FieldKeyTraceID = "trace_id"
+	// FieldKeySpanID the key for the span id. see TraceProcessor
+	FieldKeySpanID = "span_id"
+	// FieldKeyTraceFlags the key for the trace flags. see TraceProcessor
+	FieldKeyTraceFlags = "trace_flags"
+	// FieldKeySuppressed the key for the suppressed count of the rate limited logs. see Logger.Every
+	FieldKeySuppressed = "suppressed"
+	// FieldKeyRepeated the key for the repeated count of the collapsed logs. see handler.DedupHandler
+	FieldKeyRepeated = "repeated"
+	// FieldKeyPanic the key for the recovered panic value. see Logger.Recover
+	FieldKeyPanic = "panic"
 	// FieldKeyExtra key name
 	FieldKeyExtra = "extra"
 
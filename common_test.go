@@ -122,6 +122,30 @@ func TestLevel_methods(t *testing.T) {
 	})
 }
 
+func TestLevelVar(t *testing.T) {
+	lv := &slog.LevelVar{}
+	assert.Eq(t, slog.PanicLevel, lv.Level())
+
+	lv = slog.NewLevelVar(slog.InfoLevel)
+	assert.Eq(t, slog.InfoLevel, lv.Level())
+	assert.Eq(t, "INFO", lv.String())
+	assert.True(t, lv.ShouldHandling(slog.ErrorLevel))
+	assert.False(t, lv.ShouldHandling(slog.DebugLevel))
+
+	lv.Set(slog.DebugLevel)
+	assert.True(t, lv.ShouldHandling(slog.DebugLevel))
+	lv.SetByName("warn")
+	assert.Eq(t, slog.WarnLevel, lv.Level())
+
+	// encoding
+	bs, err := lv.MarshalJSON()
+	assert.NoErr(t, err)
+	assert.Eq(t, `"WARNING"`, string(bs))
+	assert.NoErr(t, lv.UnmarshalJSON([]byte(`"error"`)))
+	assert.Eq(t, slog.ErrorLevel, lv.Level())
+	assert.Err(t, lv.UnmarshalJSON([]byte(`a`)))
+}
+
 func TestLevels_Contains(t *testing.T) {
 	assert.True(t, slog.DangerLevels.Contains(slog.ErrorLevel))
 	assert.False(t, slog.DangerLevels.Contains(slog.InfoLevel))
@@ -0,0 +1,105 @@
+package slog
+
+import "context"
+
+// the context key for save the SubLogger
+type ctxLoggerKey struct{}
+
+// SubLoggerProvider can create a SubLogger. eg: *Logger, *SugaredLogger, *SubLogger
+type SubLoggerProvider interface {
+	NewSub() *SubLogger
+}
+
+// NewContext returns a new context that carries a SubLogger created by the l.
+//
+// Usage:
+//
+//	sub := logger.NewSub().KeepField("request_id", reqID)
+//	ctx = slog.NewContext(ctx, sub)
+//
+//	// then in other funcs
+//	slog.FromContext(ctx).Info("some message")
+func NewContext(ctx context.Context, l SubLoggerProvider) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, ctxLoggerKey{}, l.NewSub())
+}
+
+// FromContext returns the SubLogger carried by the ctx, which will keep the ctx for all log records.
+//
+// If the ctx has no logger, will return a new SubLogger of the std logger.
+//
+// The kept fields and data are copied, modify them will not affect the carried one.
+func FromContext(ctx context.Context) *SubLogger {
+	if ctx == nil {
+		return NewSub()
+	}
+
+	sub, ok := ctx.Value(ctxLoggerKey{}).(*SubLogger)
+	if !ok {
+		return NewSub().KeepCtx(ctx)
+	}
+
+	return &SubLogger{
+		l:      sub.l,
+		Ctx:    ctx,
+		Fields: copyM(sub.Fields),
+		Data:   copyM(sub.Data),
+		Extra:  copyM(sub.Extra),
+	}
+}
+
+// CtxKey the typed context key, its value can be extracted to record by the ContextKeysProcessor.
+//
+// see ContextKey, NewContextKey()
+type CtxKey interface {
+	// Name of the key, use for the field name on extract to record.
+	Name() string
+	// AnyValue get the value from context.
+	AnyValue(ctx context.Context) (any, bool)
+}
+
+// ContextKey a typed context key, it avoids the collision of the plain string keys.
+//
+// Usage:
+//
+//	var RequestID = slog.NewContextKey[string]("request_id")
+//
+//	ctx = RequestID.WithValue(ctx, "abc123")
+//	id, ok := RequestID.Value(ctx)
+//
+//	// extract the value to Record.Fields
+//	logger.AddProcessor(slog.ContextKeysProcessor("fields", RequestID))
+type ContextKey[T any] struct {
+	name string
+}
+
+// NewContextKey create a new typed context key.
+func NewContextKey[T any](name string) *ContextKey[T] {
+	return &ContextKey[T]{name: name}
+}
+
+// Name of the key
+func (k *ContextKey[T]) Name() string { return k.name }
+
+// String of the key
+func (k *ContextKey[T]) String() string { return "slog.ContextKey(" + k.name + ")" }
+
+// WithValue returns a new context with the value of the key.
+func (k *ContextKey[T]) WithValue(ctx context.Context, val T) context.Context {
+	return context.WithValue(ctx, k, val)
+}
+
+// Value get the value of the key from context.
+func (k *ContextKey[T]) Value(ctx context.Context) (val T, ok bool) {
+	if ctx != nil {
+		val, ok = ctx.Value(k).(T)
+	}
+	return
+}
+
+// AnyValue get the value of the key from context.
+func (k *ContextKey[T]) AnyValue(ctx context.Context) (any, bool) {
+	return k.Value(ctx)
+}
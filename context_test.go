@@ -0,0 +1,96 @@
+package slog_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+var (
+	requestIDKey = slog.NewContextKey[string]("request_id")
+	userIDKey    = slog.NewContextKey[int]("user_id")
+)
+
+func TestNewContext(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+	l.AddProcessor(slog.ContextKeysProcessor("fields", requestIDKey))
+
+	sub := l.NewSub().KeepField("app", "demo")
+	ctx := slog.NewContext(context.Background(), sub)
+	ctx = requestIDKey.WithValue(ctx, "abc123")
+
+	// modify the origin sub logger, not affect the carried one
+	sub.KeepField("other", "value")
+
+	sl := slog.FromContext(ctx)
+	sl.Info("info message")
+	s := h.ResetGet()
+	assert.StrContains(t, s, `"app":"demo"`)
+	assert.StrContains(t, s, `"request_id":"abc123"`)
+	assert.NotContains(t, s, `"other"`)
+
+	// derive a new sub logger
+	sl.NewSub().KeepField("step", 2).Info("info message")
+	s = h.ResetGet()
+	assert.StrContains(t, s, `"app":"demo"`)
+	assert.StrContains(t, s, `"step":2`)
+	slog.FromContext(ctx).Info("info message")
+	assert.NotContains(t, h.ResetGet(), `"step"`)
+
+	// modify the returned one, not affect the carried one
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			slog.FromContext(ctx).KeepField("worker", i).KeepData(slog.M{"key": i})
+		}(i)
+	}
+	wg.Wait()
+	slog.FromContext(ctx).Info("info message")
+	assert.NotContains(t, h.ResetGet(), `"worker"`)
+
+	// no logger in context: use std logger
+	assert.NotNil(t, slog.FromContext(context.Background()))
+	assert.NotNil(t, slog.FromContext(nil))
+}
+
+func TestContextKeysProcessor(t *testing.T) {
+	ctx := requestIDKey.WithValue(context.Background(), "abc123")
+	ctx = userIDKey.WithValue(ctx, 23)
+
+	id, ok := requestIDKey.Value(ctx)
+	assert.True(t, ok)
+	assert.Eq(t, "abc123", id)
+	uid, ok := userIDKey.Value(ctx)
+	assert.True(t, ok)
+	assert.Eq(t, 23, uid)
+	_, ok = userIDKey.Value(context.Background())
+	assert.False(t, ok)
+
+	// not collision with the plain string key
+	assert.Nil(t, ctx.Value("request_id"))
+	assert.Eq(t, "slog.ContextKey(request_id)", requestIDKey.String())
+
+	tests := []struct {
+		dist string
+		get  func(r *slog.Record) slog.M
+	}{
+		{"data", func(r *slog.Record) slog.M { return r.Data }},
+		{"fields", func(r *slog.Record) slog.M { return r.Fields }},
+		{"extra", func(r *slog.Record) slog.M { return r.Extra }},
+	}
+	for _, tt := range tests {
+		r := newLogRecord("test")
+		r.Data, r.Fields, r.Extra = nil, nil, nil
+		r.Ctx = ctx
+
+		slog.ContextKeysProcessor(tt.dist, requestIDKey, userIDKey).Process(r)
+		assert.Eq(t, slog.M{"request_id": "abc123", "user_id": 23}, tt.get(r))
+	}
+}
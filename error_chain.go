@@ -0,0 +1,300 @@
+package slog
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+)
+
+// max depth of the error chain, avoid the cyclic Unwrap()
+const maxErrorChainDepth = 32
+
+// ErrorEntry an error in the unwrapped error chain. see ErrorChain()
+//
+// The optional interfaces of the error will be used:
+//
+//   - Code() int, Code() string: for the Code
+//   - Fields() map[string]any, Fields() M: for the Fields
+//   - StackTrace() []StackFrame, StackTrace() []uintptr, StackFrames() *runtime.Frames: for the Stack
+//   - StackTrace() T, the T is a slice of the uintptr kind. eg: github.com/pkg/errors.StackTrace
+type ErrorEntry struct {
+	// Message of the error, it is err.Error()
+	Message string `json:"message"`
+	// Type name of the error. eg: "*fs.PathError"
+	Type   string       `json:"type"`
+	Code   any          `json:"code,omitempty"`
+	Fields M            `json:"fields,omitempty"`
+	Stack  []StackFrame `json:"stack,omitempty"`
+	// Errors the chains of the joined errors. eg: errors.Join(err1, err2)
+	Errors [][]ErrorEntry `json:"errors,omitempty"`
+}
+
+// ErrorChain unwrap the error to a chain by errors.Unwrap(). the first entry is the err self.
+//
+// The multi errors with Unwrap() []error will be added to ErrorEntry.Errors, and end the chain.
+func ErrorChain(err error) []ErrorEntry {
+	return errorChain(err, 0)
+}
+
+func errorChain(err error, depth int) []ErrorEntry {
+	var chain []ErrorEntry
+	for err != nil && depth < maxErrorChainDepth {
+		depth++
+		entry := newErrorEntry(err)
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, sub := range u.Unwrap() {
+				if sub != nil {
+					entry.Errors = append(entry.Errors, errorChain(sub, depth))
+				}
+			}
+			err = nil
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			err = nil
+		}
+		chain = append(chain, entry)
+	}
+	return chain
+}
+
+func newErrorEntry(err error) ErrorEntry {
+	entry := ErrorEntry{
+		Message: err.Error(),
+		Type:    fmt.Sprintf("%T", err),
+	}
+
+	switch e := err.(type) {
+	case interface{ Code() int }:
+		entry.Code = e.Code()
+	case interface{ Code() string }:
+		entry.Code = e.Code()
+	}
+
+	switch e := err.(type) {
+	case interface{ Fields() map[string]any }:
+		entry.Fields = e.Fields()
+	case interface{ Fields() M }:
+		entry.Fields = e.Fields()
+	}
+
+	switch e := err.(type) {
+	case interface{ StackTrace() []StackFrame }:
+		entry.Stack = e.StackTrace()
+	case interface{ StackTrace() []uintptr }:
+		entry.Stack = framesToStack(runtime.CallersFrames(e.StackTrace()))
+	case interface{ StackFrames() *runtime.Frames }:
+		entry.Stack = stackFramesOf(e)
+	default:
+		entry.Stack = reflectStackTrace(err)
+	}
+	return entry
+}
+
+// get the stack from StackTrace() T, the T is a slice of the uintptr kind.
+// eg: github.com/pkg/errors, its StackTrace is []Frame and the Frame is uintptr.
+func reflectStackTrace(err error) (stack []StackFrame) {
+	defer func() {
+		if recover() != nil {
+			stack = nil
+		}
+	}()
+
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() {
+		return nil
+	}
+
+	mt := m.Type()
+	if mt.NumIn() != 0 || mt.NumOut() != 1 {
+		return nil
+	}
+	if rt := mt.Out(0); rt.Kind() != reflect.Slice || rt.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	rv := m.Call(nil)[0]
+	if rv.Len() == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, rv.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(rv.Index(i).Uint())
+	}
+	return framesToStack(runtime.CallersFrames(pcs))
+}
+
+// get stack from StackFrames(). eg: gookit/goutil/errorx.ErrorX, it will panic on the stack is nil.
+func stackFramesOf(e interface{ StackFrames() *runtime.Frames }) (stack []StackFrame) {
+	defer func() {
+		if recover() != nil {
+			stack = nil
+		}
+	}()
+
+	if frames := e.StackFrames(); frames != nil {
+		stack = framesToStack(frames)
+	}
+	return
+}
+
+func framesToStack(frames *runtime.Frames) []StackFrame {
+	var stack []StackFrame
+	for {
+		fr, more := frames.Next()
+		if fr.PC != 0 {
+			stack = append(stack, StackFrame{Function: fr.Function, File: fr.File, Line: fr.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// check the chain has more info than the err.Error()
+func isDetailedChain(chain []ErrorEntry) bool {
+	if len(chain) != 1 {
+		return len(chain) > 1
+	}
+
+	e := &chain[0]
+	return e.Code != nil || len(e.Fields) > 0 || len(e.Stack) > 0 || len(e.Errors) > 0
+}
+
+// append the error chain as JSON array. eg: [{"message":"a: b","type":"*fmt.wrapError"},{...}]
+func appendJSONErrorChain(b []byte, chain []ErrorEntry) []byte {
+	b = append(b, '[')
+	for i := range chain {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		e := &chain[i]
+		b = append(b, `{"message":`...)
+		b = appendJSONString(b, e.Message)
+		b = append(b, `,"type":`...)
+		b = appendJSONString(b, e.Type)
+		if e.Code != nil {
+			b = append(b, `,"code":`...)
+			b, _ = appendJSONValue(b, e.Code)
+		}
+		if len(e.Fields) > 0 {
+			b = append(b, `,"fields":`...)
+			b, _ = appendJSONValue(b, e.Fields)
+		}
+		if len(e.Stack) > 0 {
+			b = append(b, `,"stack":`...)
+			b = appendJSONStack(b, e.Stack)
+		}
+		if len(e.Errors) > 0 {
+			b = append(b, `,"errors":[`...)
+			for j, sub := range e.Errors {
+				if j > 0 {
+					b = append(b, ',')
+				}
+				b = appendJSONErrorChain(b, sub)
+			}
+			b = append(b, ']')
+		}
+		b = append(b, '}')
+	}
+	return append(b, ']')
+}
+
+// append the error chain as indented multi lines, first is the prefix of the first entry. eg:
+//
+//	"\n\terror: a: b (*fmt.wrapError)\n\tcaused by: b (*errors.errorString)"
+func appendTextErrorChain(b []byte, chain []ErrorEntry, indent, first string) []byte {
+	for i := range chain {
+		e := &chain[i]
+		b = append(b, '\n')
+		b = append(b, indent...)
+		if i == 0 {
+			b = append(b, first...)
+		} else {
+			b = append(b, "caused by: "...)
+		}
+
+		b = append(b, e.Message...)
+		b = append(b, " ("...)
+		b = append(b, e.Type...)
+		b = append(b, ')')
+		if e.Code != nil {
+			b = append(b, " code="...)
+			b = append(b, EncodeToString(e.Code)...)
+		}
+		if len(e.Fields) > 0 {
+			b = append(b, " fields="...)
+			b = append(b, EncodeToString(e.Fields)...)
+		}
+
+		for _, sf := range e.Stack {
+			b = append(b, '\n')
+			b = append(b, indent...)
+			b = append(b, '\t')
+			b = append(b, sf.Function...)
+			b = append(b, "()\n"...)
+			b = append(b, indent...)
+			b = append(b, "\t\t"...)
+			b = append(b, sf.File...)
+			b = append(b, ':')
+			b = strconv.AppendInt(b, int64(sf.Line), 10)
+		}
+
+		if len(e.Errors) > 0 {
+			b = append(b, '\n')
+			b = append(b, indent...)
+			b = append(b, "caused by "...)
+			b = strconv.AppendInt(b, int64(len(e.Errors)), 10)
+			b = append(b, " errors:"...)
+			for _, sub := range e.Errors {
+				b = appendTextErrorChain(b, sub, indent+"\t", "- ")
+			}
+		}
+	}
+	return b
+}
+
+// convert the error values to ErrorChain() for encode by encoding/json. the map will be copied on changed.
+func toJSONErrorValue(v any) (any, bool) {
+	switch val := v.(type) {
+	case json.Marshaler:
+		return v, false
+	case error:
+		return ErrorChain(val), true
+	case M:
+		return convertMapErrors(val)
+	case map[string]any:
+		return convertMapErrors(val)
+	}
+	return v, false
+}
+
+func convertMapErrors(m map[string]any) (map[string]any, bool) {
+	var nm map[string]any
+	for k, v := range m {
+		nv, changed := toJSONErrorValue(v)
+		if !changed {
+			continue
+		}
+
+		if nm == nil {
+			nm = make(map[string]any, len(m))
+			for k1, v1 := range m {
+				nm[k1] = v1
+			}
+		}
+		nm[k] = nv
+	}
+
+	if nm == nil {
+		return m, false
+	}
+	return nm, true
+}
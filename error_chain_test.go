@@ -0,0 +1,188 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/errorx"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+// codeError an error with code, fields and stack
+type codeError struct {
+	code int
+	msg  string
+}
+
+func (e *codeError) Error() string  { return e.msg }
+func (e *codeError) Code() int      { return e.code }
+func (e *codeError) Fields() slog.M { return slog.M{"user": "inhere"} }
+func (e *codeError) StackTrace() []slog.StackFrame {
+	return []slog.StackFrame{{Function: "main.handler", File: "/app/main.go", Line: 23}}
+}
+
+// multiError an error with Unwrap() []error, like errors.Join()
+type multiError []error
+
+func (es multiError) Error() string {
+	ss := make([]string, len(es))
+	for i, err := range es {
+		ss[i] = err.Error()
+	}
+	return strings.Join(ss, "\n")
+}
+
+func (es multiError) Unwrap() []error { return es }
+
+// the stack types are same as github.com/pkg/errors
+type (
+	pkgFrame      uintptr
+	pkgStackTrace []pkgFrame
+)
+
+// pkgError an error with StackTrace() like github.com/pkg/errors
+type pkgError struct {
+	msg   string
+	stack []uintptr
+}
+
+func newPkgError(msg string) *pkgError {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &pkgError{msg: msg, stack: pcs[:n]}
+}
+
+func (e *pkgError) Error() string { return e.msg }
+
+func (e *pkgError) StackTrace() pkgStackTrace {
+	st := make(pkgStackTrace, len(e.stack))
+	for i, pc := range e.stack {
+		st[i] = pkgFrame(pc)
+	}
+	return st
+}
+
+func TestErrorChain(t *testing.T) {
+	assert.Empty(t, slog.ErrorChain(nil))
+
+	ce := &codeError{code: 404, msg: "user not found"}
+	err := fmt.Errorf("query user: %w", ce)
+	chain := slog.ErrorChain(err)
+	assert.Len(t, chain, 2)
+	assert.Eq(t, "query user: user not found", chain[0].Message)
+	assert.Eq(t, "*fmt.wrapError", chain[0].Type)
+	assert.Nil(t, chain[0].Code)
+	assert.Eq(t, "user not found", chain[1].Message)
+	assert.Eq(t, "*slog_test.codeError", chain[1].Type)
+	assert.Eq(t, 404, chain[1].Code)
+	assert.Eq(t, slog.M{"user": "inhere"}, chain[1].Fields)
+	assert.Eq(t, "main.handler", chain[1].Stack[0].Function)
+
+	// multi errors
+	err = fmt.Errorf("save: %w", multiError{errors.New("err1"), fmt.Errorf("err2: %w", ce)})
+	chain = slog.ErrorChain(err)
+	assert.Len(t, chain, 2)
+	assert.Eq(t, "slog_test.multiError", chain[1].Type)
+	assert.Len(t, chain[1].Errors, 2)
+	assert.Len(t, chain[1].Errors[0], 1)
+	assert.Len(t, chain[1].Errors[1], 2)
+	assert.Eq(t, 404, chain[1].Errors[1][1].Code)
+
+	// goutil errorx with stack
+	chain = slog.ErrorChain(errorx.New("error with stack"))
+	assert.Len(t, chain, 1)
+	assert.NotEmpty(t, chain[0].Stack)
+	assert.Eq(t, "github.com/gookit/slog_test.TestErrorChain", chain[0].Stack[0].Function)
+	chain = slog.ErrorChain(errorx.Raw("error without stack"))
+	assert.Empty(t, chain[0].Stack)
+
+	// pkg/errors like stack
+	chain = slog.ErrorChain(fmt.Errorf("wrap: %w", newPkgError("pkg error")))
+	assert.Len(t, chain, 2)
+	assert.Empty(t, chain[0].Stack)
+	assert.NotEmpty(t, chain[1].Stack)
+	assert.Eq(t, "github.com/gookit/slog_test.TestErrorChain", chain[1].Stack[0].Function)
+	assert.StrContains(t, chain[1].Stack[0].File, "error_chain_test.go")
+}
+
+func TestLogger_ErrorT_json(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+
+	err := fmt.Errorf("query user: %w", &codeError{code: 404, msg: "user not found"})
+	l.ErrorT(err)
+
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(h.Bytes(), &m))
+	assert.Eq(t, err.Error(), m["message"])
+	assert.Eq(t, []any{
+		map[string]any{"message": "query user: user not found", "type": "*fmt.wrapError"},
+		map[string]any{
+			"message": "user not found",
+			"type":    "*slog_test.codeError",
+			"code":    float64(404),
+			"fields":  map[string]any{"user": "inhere"},
+			"stack":   []any{map[string]any{"function": "main.handler", "file": "/app/main.go", "line": float64(23)}},
+		},
+	}, m["error"])
+
+	// error in data, pretty print
+	h.Reset()
+	h.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.PrettyPrint = true
+	}))
+	l.WithData(slog.M{"err": multiError{errors.New("err1")}}).Warn("warn message")
+
+	m = map[string]any{}
+	assert.NoErr(t, json.Unmarshal(h.Bytes(), &m))
+	assert.Eq(t, map[string]any{"err": []any{map[string]any{
+		"message": "err1",
+		"type":    "slog_test.multiError",
+		"errors":  []any{[]any{map[string]any{"message": "err1", "type": "*errors.errorString"}}},
+	}}}, m["data"])
+}
+
+func TestLogger_ErrorT_text(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l := slog.NewWithHandlers(h)
+
+	// simple error: not changed
+	l.ErrorT(errors.New("simple error"))
+	assert.Eq(t, "ERROR simple error\n", h.ResetGet())
+
+	ce := &codeError{code: 404, msg: "user not found"}
+	l.ErrorT(fmt.Errorf("save: %w", multiError{errors.New("err1"), ce}))
+	assert.Eq(t, `ERROR save: err1
+user not found
+	error: save: err1
+user not found (*fmt.wrapError)
+	caused by: err1
+user not found (slog_test.multiError)
+	caused by 2 errors:
+		- err1 (*errors.errorString)
+		- user not found (*slog_test.codeError) code=404 fields={user:inhere}
+			main.handler()
+				/app/main.go:23
+`, h.ResetGet())
+
+	// with error: not in template
+	l.Record().WithError(errors.New("simple error")).Info("info message")
+	assert.Eq(t, "INFO info message\n\terror: simple error (*errors.errorString)\n", h.ResetGet())
+
+	// with error: in template
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}} err={{error}}\n"))
+	l.Record().WithError(errors.New("simple error")).Info("info message")
+	assert.Eq(t, "INFO info message err=simple error\n", h.ResetGet())
+
+	// typed error field
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l.Build(slog.WarnLevel).Err(fmt.Errorf("wrap: %w", errors.New("inner"))).Msg("typed error")
+	assert.Eq(t, "WARNING typed error error=\"wrap: inner\"\n\terror: wrap: inner (*fmt.wrapError)\n\tcaused by: inner (*errors.errorString)\n", h.ResetGet())
+}
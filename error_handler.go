@@ -0,0 +1,307 @@
+package slog
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// HandlerOp the operation name of a Handler
+type HandlerOp string
+
+// there are handler operation names
+const (
+	OpHandle HandlerOp = "handle"
+	OpFlush  HandlerOp = "flush"
+	OpClose  HandlerOp = "close"
+)
+
+// HandlerError the error information of a failed handler operation.
+type HandlerError struct {
+	// Logger the logger of the handler
+	Logger *Logger
+	// Op the failed operation. eg: OpHandle, OpFlush, OpClose
+	Op HandlerOp
+	// Handler the failing handler
+	Handler Handler
+	// Record the log record on handle failed. it is nil on flush, close.
+	//
+	// NOTE: the record will be reused after handled, don't keep it. please use Record.Clone() if necessary.
+	Record *Record
+	// Err the error returned by the handler
+	Err error
+	// Panic the value recovered from the handler panic. it is nil if the handler not panicked.
+	Panic any
+	// Stack the call stack on the handler panic.
+	Stack []byte
+	// Failures the number of consecutive failures of the handler.
+	Failures int
+	// Disable set true to disable the handler, the logger will not call it anymore.
+	//
+	// TIP: can be enabled again by Logger.EnableHandler()
+	Disable bool
+}
+
+// Error string of the handler error
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("slog: call handler.%s() error: %v", e.Op, e.Err)
+}
+
+// Unwrap get the raw error
+func (e *HandlerError) Unwrap() error { return e.Err }
+
+// ErrorHandler interface. handle the errors from Handler.Handle, Flush, Close and recovered panics.
+//
+// NOTE: it is called with the logger lock held, don't log messages to the same logger in it.
+type ErrorHandler interface {
+	HandleError(e *HandlerError)
+}
+
+// ErrorHandlerFunc wrapper definition
+type ErrorHandlerFunc func(e *HandlerError)
+
+// HandleError implements the ErrorHandler
+func (fn ErrorHandlerFunc) HandleError(e *HandlerError) { fn(e) }
+
+//
+// there are some built-in error handlers
+//
+
+// IgnoreErrors returns an ErrorHandler that ignores all handler errors.
+func IgnoreErrors() ErrorHandler {
+	return ErrorHandlerFunc(func(e *HandlerError) {})
+}
+
+// ErrorCounter an ErrorHandler that counts the handler errors.
+type ErrorCounter struct {
+	total  atomic.Uint64
+	panics atomic.Uint64
+	// count by operation
+	mu   sync.Mutex
+	byOp map[HandlerOp]uint64
+	// Next error handler, will be called after counted. can be nil.
+	Next ErrorHandler
+}
+
+// NewErrorCounter instance
+func NewErrorCounter(next ...ErrorHandler) *ErrorCounter {
+	c := &ErrorCounter{byOp: make(map[HandlerOp]uint64)}
+	if len(next) > 0 {
+		c.Next = next[0]
+	}
+	return c
+}
+
+// HandleError implements the ErrorHandler
+func (c *ErrorCounter) HandleError(e *HandlerError) {
+	c.total.Add(1)
+	if e.Panic != nil {
+		c.panics.Add(1)
+	}
+
+	c.mu.Lock()
+	c.byOp[e.Op]++
+	c.mu.Unlock()
+
+	if c.Next != nil {
+		c.Next.HandleError(e)
+	}
+}
+
+// Count get the total number of errors
+func (c *ErrorCounter) Count() uint64 { return c.total.Load() }
+
+// PanicCount get the number of recovered handler panics
+func (c *ErrorCounter) PanicCount() uint64 { return c.panics.Load() }
+
+// CountOf get the number of errors by operation
+func (c *ErrorCounter) CountOf(op HandlerOp) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.byOp[op]
+}
+
+// Reset the counter
+func (c *ErrorCounter) Reset() {
+	c.total.Store(0)
+	c.panics.Store(0)
+	c.mu.Lock()
+	c.byOp = make(map[HandlerOp]uint64)
+	c.mu.Unlock()
+}
+
+// FallbackOnError returns an ErrorHandler that logs to the fallback handler.
+//
+//   - on handle failed: the failed record will be re-sent to the fallback handler.
+//   - on flush, close failed: a new error record will be sent to the fallback handler.
+func FallbackOnError(fallback Handler) ErrorHandler {
+	return ErrorHandlerFunc(func(e *HandlerError) {
+		var err error
+		if e.Record != nil {
+			if !fallback.IsHandling(e.Record.Level) {
+				return
+			}
+			err = fallback.Handle(e.Record)
+		} else if fallback.IsHandling(ErrorLevel) {
+			err = fallback.Handle(newErrorRecord(e))
+		}
+
+		if err != nil {
+			printStderr("slog: fallback handler error:", err, "raw error:", e.Err)
+		}
+	})
+}
+
+// DisableOnErrors returns an ErrorHandler that disables the handler after n consecutive failures.
+//
+// The next error handler will be called before checking. if next is empty, will print error to stderr.
+func DisableOnErrors(n int, next ...ErrorHandler) ErrorHandler {
+	return ErrorHandlerFunc(func(e *HandlerError) {
+		if len(next) > 0 {
+			next[0].HandleError(e)
+		} else {
+			printStderr(e.Error())
+		}
+
+		if e.Failures >= n {
+			e.Disable = true
+			printStderr("slog: disable the handler after", e.Failures, "consecutive failures")
+		}
+	})
+}
+
+// build an error record for report the handler error
+func newErrorRecord(e *HandlerError) *Record {
+	r := &Record{
+		logger:  e.Logger,
+		Level:   ErrorLevel,
+		Channel: DefaultChannelName,
+		Message: e.Error(),
+		Time:    DefaultClockFn.Now(),
+		Data:    M{},
+	}
+
+	if e.Logger != nil {
+		r.Channel = e.Logger.ChannelName
+		r.Time = e.Logger.TimeClock.Now()
+	}
+
+	r.Init(false)
+	return r
+}
+
+//
+// ---------------------------------------------------------------------------
+// region Handler calling in the Logger
+// ---------------------------------------------------------------------------
+//
+
+// SetErrorHandler set the error handler for the logger
+func (l *Logger) SetErrorHandler(eh ErrorHandler) { l.ErrorHandler = eh }
+
+// DisableHandler disable the handler, the logger will not call it anymore.
+func (l *Logger) DisableHandler(h Handler) {
+	l.mu.Lock()
+	l.disableHandler(h)
+	l.mu.Unlock()
+}
+
+// EnableHandler enable the disabled handler, and reset its failure count.
+func (l *Logger) EnableHandler(h Handler) {
+	l.mu.Lock()
+	delete(l.disabled, h)
+	delete(l.failures, h)
+	l.mu.Unlock()
+}
+
+// IsDisabledHandler check the handler is disabled
+func (l *Logger) IsDisabledHandler(h Handler) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.isDisabled(h)
+}
+
+// l.mu is held.
+func (l *Logger) disableHandler(h Handler) {
+	if l.disabled == nil {
+		l.disabled = make(map[Handler]bool)
+	}
+	l.disabled[h] = true
+}
+
+// l.mu is held.
+func (l *Logger) isDisabled(h Handler) bool {
+	return len(l.disabled) > 0 && l.disabled[h]
+}
+
+// callHandler call the handler operation, the error and panic will be reported
+// to the ErrorHandler. l.mu is held.
+func (l *Logger) callHandler(op HandlerOp, h Handler, r *Record) {
+	pv, stack, err := invokeHandler(op, h, r)
+	if err == nil {
+		// reset the consecutive failures on success
+		if op == OpHandle && len(l.failures) > 0 {
+			delete(l.failures, h)
+		}
+		return
+	}
+
+	l.handleError(&HandlerError{
+		Op:      op,
+		Handler: h,
+		Record:  r,
+		Err:     err,
+		Panic:   pv,
+		Stack:   stack,
+	})
+}
+
+// handleError report the handler error. l.mu is held.
+func (l *Logger) handleError(e *HandlerError) {
+	e.Logger = l
+	l.err = e.Err
+
+	if l.failures == nil {
+		l.failures = make(map[Handler]int)
+	}
+	l.failures[e.Handler]++
+	e.Failures = l.failures[e.Handler]
+
+	if l.ErrorHandler == nil {
+		switch e.Op {
+		case OpHandle:
+			printStderr("slog: failed to handle log, error:", e.Err)
+		case OpFlush:
+			printStderr("slog: call handler.Flush() error:", e.Err)
+		default:
+			printStderr("slog: call handler.Close() error:", e.Err)
+		}
+		return
+	}
+
+	l.ErrorHandler.HandleError(e)
+	if e.Disable {
+		l.disableHandler(e.Handler)
+	}
+}
+
+// invokeHandler call the handler operation, and recover the panic as error.
+func invokeHandler(op HandlerOp, h Handler, r *Record) (pv any, stack []byte, err error) {
+	defer func() {
+		if pv = recover(); pv != nil {
+			stack = debug.Stack()
+			err = fmt.Errorf("slog: handler panic: %v", pv)
+		}
+	}()
+
+	switch op {
+	case OpHandle:
+		err = h.Handle(r)
+	case OpFlush:
+		err = h.Flush()
+	default:
+		err = h.Close()
+	}
+	return
+}
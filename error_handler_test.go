@@ -0,0 +1,172 @@
+package slog_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+type panicHandler struct {
+	testHandler
+}
+
+func (h *panicHandler) Handle(_ *slog.Record) error {
+	panic("handler panic")
+}
+
+func TestLogger_ErrorHandler(t *testing.T) {
+	h := newTestHandler()
+	h.errOnHandle = true
+	h.errOnFlush = true
+	h.errOnClose = true
+
+	var ops []slog.HandlerOp
+	c := slog.NewErrorCounter(slog.ErrorHandlerFunc(func(e *slog.HandlerError) {
+		ops = append(ops, e.Op)
+		assert.Eq(t, h, e.Handler)
+		if e.Op == slog.OpHandle {
+			assert.NotNil(t, e.Record)
+			assert.Eq(t, "a message", e.Record.Message)
+		} else {
+			assert.Nil(t, e.Record)
+		}
+	}))
+
+	l := slog.NewWithHandlers(h)
+	l.SetErrorHandler(c)
+
+	l.Info("a message")
+	assert.ErrMsg(t, l.Flush(), "flush error")
+	assert.Err(t, l.Close())
+
+	assert.Eq(t, uint64(3), c.Count())
+	assert.Eq(t, uint64(0), c.PanicCount())
+	assert.Eq(t, uint64(1), c.CountOf(slog.OpHandle))
+	assert.Eq(t, uint64(1), c.CountOf(slog.OpClose))
+	assert.Eq(t, []slog.HandlerOp{slog.OpHandle, slog.OpFlush, slog.OpClose}, ops)
+	assert.ErrMsg(t, l.LastErr(), "close error")
+
+	c.Reset()
+	assert.Eq(t, uint64(0), c.Count())
+	assert.Eq(t, uint64(0), c.CountOf(slog.OpHandle))
+}
+
+func TestLogger_ErrorHandler_panic(t *testing.T) {
+	h := &panicHandler{}
+	h2 := newTestHandler()
+
+	c := slog.NewErrorCounter(slog.IgnoreErrors())
+	l := slog.NewWithHandlers(h, h2)
+	l.ErrorHandler = c
+
+	assert.NotPanics(t, func() {
+		l.Info("a message")
+	})
+	assert.Eq(t, uint64(1), c.PanicCount())
+	assert.StrContains(t, h2.String(), "a message")
+	assert.StrContains(t, l.LastErr().Error(), "handler panic")
+
+	var he *slog.HandlerError
+	l.SetErrorHandler(slog.ErrorHandlerFunc(func(e *slog.HandlerError) {
+		he = e
+	}))
+	l.Warn("a warning")
+	assert.NotNil(t, he)
+	assert.Eq(t, "handler panic", he.Panic)
+	assert.NotEmpty(t, he.Stack)
+	assert.StrContains(t, he.Error(), "slog: call handler.handle() error")
+	assert.True(t, errors.Is(he, he.Err))
+}
+
+func TestFallbackOnError(t *testing.T) {
+	h := newTestHandler()
+	h.errOnHandle = true
+	h.errOnFlush = true
+	fb := newTestHandler()
+
+	l := slog.NewWithHandlers(h)
+	l.ChannelName = "test"
+	l.DoNothingOnPanicFatal()
+	l.ErrorHandler = slog.FallbackOnError(fb)
+
+	l.Info("a message")
+	assert.StrContains(t, fb.String(), "a message")
+
+	fb.Reset()
+	assert.Err(t, l.Flush())
+	s := fb.String()
+	assert.StrContains(t, s, "[test]")
+	assert.StrContains(t, s, "ERROR")
+	assert.StrContains(t, s, "slog: call handler.flush() error: flush error")
+}
+
+func TestDisableOnErrors(t *testing.T) {
+	h := newTestHandler()
+	h.errOnHandle = true
+	h2 := newTestHandler()
+
+	c := slog.NewErrorCounter()
+	l := slog.NewWithHandlers(h, h2)
+	l.ErrorHandler = slog.DisableOnErrors(3, c)
+
+	l.Info("message 1")
+	l.Info("message 2")
+	assert.False(t, l.IsDisabledHandler(h))
+
+	// success will reset the consecutive failures
+	h.errOnHandle = false
+	l.Info("message 3")
+	h.errOnHandle = true
+	l.Info("message 4")
+	l.Info("message 5")
+	assert.False(t, l.IsDisabledHandler(h))
+
+	l.Info("message 6")
+	assert.True(t, l.IsDisabledHandler(h))
+	assert.Eq(t, uint64(5), c.Count())
+
+	// disabled handler will not be called
+	l.Info("message 7")
+	assert.Eq(t, uint64(5), c.Count())
+	assert.StrContains(t, h2.String(), "message 7")
+
+	l.EnableHandler(h)
+	assert.False(t, l.IsDisabledHandler(h))
+	l.Info("message 8")
+	assert.Eq(t, uint64(6), c.Count())
+
+	l.DisableHandler(h2)
+	h2.Reset()
+	l.Info("message 9")
+	assert.Empty(t, h2.String())
+}
+
+func TestSugaredLogger_FlushAll_ErrorHandler(t *testing.T) {
+	h := newTestHandler()
+	h.errOnFlush = true
+
+	c := slog.NewErrorCounter(slog.IgnoreErrors())
+	sl := slog.NewStdLogger()
+	sl.AddHandler(h)
+	sl.SetErrorHandler(c)
+
+	assert.ErrMsg(t, sl.FlushAll(), "flush error")
+	assert.Eq(t, uint64(1), c.CountOf(slog.OpFlush))
+
+	h2 := &panicFlushHandler{}
+	sl.AddHandler(h2)
+	assert.NotPanics(t, func() {
+		_ = sl.Flush()
+	})
+	assert.Eq(t, uint64(1), c.PanicCount())
+}
+
+type panicFlushHandler struct {
+	testHandler
+}
+
+func (h *panicFlushHandler) Flush() error {
+	panic("flush panic")
+}
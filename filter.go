@@ -0,0 +1,149 @@
+package slog
+
+import (
+	"regexp"
+	"strings"
+)
+
+//
+// Filter interface
+//
+
+// Filter interface definition. decide the record should be handled or dropped.
+//
+// The Processor can mutate a record but cannot drop it, use Filter to drop the record.
+type Filter interface {
+	// Allow check the record should be handled. return false to drop it.
+	Allow(r *Record) bool
+}
+
+// FilterFunc wrapper definition
+type FilterFunc func(r *Record) bool
+
+// Allow the record
+func (fn FilterFunc) Allow(r *Record) bool {
+	return fn(r)
+}
+
+// AllowRecord check the record is allowed by all filters. returns true if filters is empty.
+func AllowRecord(r *Record, filters []Filter) bool {
+	for _, f := range filters {
+		if !f.Allow(r) {
+			return false
+		}
+	}
+	return true
+}
+
+//
+// there are some built-in filters
+//
+
+// ChannelFilter allow the records of the channels. the name ends with "*" will match by prefix.
+//
+// Usage:
+//
+//	slog.ChannelFilter("order", "pay.*")
+//	// suppress the noisy channel
+//	slog.Not(slog.ChannelFilter("gorm"))
+func ChannelFilter(channels ...string) Filter {
+	return FilterFunc(func(r *Record) bool {
+		for _, name := range channels {
+			if strings.HasSuffix(name, "*") {
+				if strings.HasPrefix(r.Channel, name[:len(name)-1]) {
+					return true
+				}
+			} else if r.Channel == name {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FieldEquals allow the records that the field value equals to the value.
+//
+// The field is searched by Record.Lookup(), the values are compared by ValueEqual().
+func FieldEquals(key string, value any) Filter {
+	return FilterFunc(func(r *Record) bool {
+		v, ok := r.Lookup(key)
+		return ok && ValueEqual(v, value)
+	})
+}
+
+// FieldExists allow the records that has the field. see FieldEquals() for the search order.
+func FieldExists(key string) Filter {
+	return FilterFunc(func(r *Record) bool {
+		_, ok := r.Lookup(key)
+		return ok
+	})
+}
+
+// MessageMatch allow the records that the message matches the regexp pattern.
+// will panic on the pattern is invalid.
+func MessageMatch(pattern string) Filter {
+	return MessageRegexp(regexp.MustCompile(pattern))
+}
+
+// MessageRegexp allow the records that the message matches the regexp
+func MessageRegexp(re *regexp.Regexp) Filter {
+	return FilterFunc(func(r *Record) bool {
+		return re.MatchString(r.Message)
+	})
+}
+
+// CallerPkgPrefix allow the records that the caller package has the prefix.
+// eg: "github.com/gookit" will match "github.com/gookit/slog", not match "github.com/gookitx".
+//
+// NOTE: it requires Logger.ReportCaller=true, the records without caller are not allowed.
+func CallerPkgPrefix(prefixes ...string) Filter {
+	return FilterFunc(func(r *Record) bool {
+		if r.Caller == nil {
+			return false
+		}
+
+		pkg := callerPkg(r.Caller.Function)
+		for _, prefix := range prefixes {
+			prefix = strings.TrimSuffix(prefix, "/")
+			if pkg == prefix || strings.HasPrefix(pkg, prefix+"/") {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Not reverse the result of the filter
+func Not(f Filter) Filter {
+	return FilterFunc(func(r *Record) bool {
+		return !f.Allow(r)
+	})
+}
+
+// And allow the records that allowed by all filters
+func And(filters ...Filter) Filter {
+	return FilterFunc(func(r *Record) bool {
+		return AllowRecord(r, filters)
+	})
+}
+
+// Or allow the records that allowed by any filter. returns false if filters is empty.
+func Or(filters ...Filter) Filter {
+	return FilterFunc(func(r *Record) bool {
+		for _, f := range filters {
+			if f.Allow(r) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// get the package path of the caller function name. eg: "github.com/gookit/slog.(*Logger).Info"
+func callerPkg(fn string) string {
+	i := strings.LastIndexByte(fn, '/')
+	if j := strings.IndexByte(fn[i+1:], '.'); j >= 0 {
+		return fn[:i+1+j]
+	}
+	return fn
+}
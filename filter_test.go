@@ -0,0 +1,103 @@
+package slog_test
+
+import (
+	"regexp"
+	"runtime"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/slogtest"
+)
+
+func TestBuiltinFilters(t *testing.T) {
+	r := &slog.Record{
+		Channel: "pay.order",
+		Message: "order created: o-001",
+		Fields:  slog.M{"user_id": 23},
+		Data:    slog.M{"amount": 1.5},
+	}
+
+	assert.True(t, slog.ChannelFilter("pay.order").Allow(r))
+	assert.True(t, slog.ChannelFilter("user", "pay.*").Allow(r))
+	assert.False(t, slog.ChannelFilter("pay", "user.*").Allow(r))
+
+	assert.True(t, slog.FieldEquals("user_id", int64(23)).Allow(r))
+	assert.True(t, slog.FieldEquals("amount", 1.5).Allow(r))
+	assert.False(t, slog.FieldEquals("user_id", "23").Allow(r))
+	assert.False(t, slog.FieldEquals("not-exists", nil).Allow(r))
+	assert.True(t, slog.FieldExists("amount").Allow(r))
+	assert.False(t, slog.FieldExists("not-exists").Allow(r))
+
+	assert.True(t, slog.MessageMatch(`^order \w+:`).Allow(r))
+	assert.False(t, slog.MessageRegexp(regexp.MustCompile(`^user`)).Allow(r))
+	assert.Panics(t, func() {
+		slog.MessageMatch(`[invalid`)
+	})
+
+	// caller
+	assert.False(t, slog.CallerPkgPrefix("github.com/gookit/slog").Allow(r))
+	r.Caller = &runtime.Frame{Function: "github.com/gookit/slog/handler.(*Builder).Build"}
+	assert.True(t, slog.CallerPkgPrefix("github.com/gookit/slog").Allow(r))
+	assert.True(t, slog.CallerPkgPrefix("github.com/gookit/slog/handler/").Allow(r))
+	assert.False(t, slog.CallerPkgPrefix("github.com/gookit/sl").Allow(r))
+	r.Caller = &runtime.Frame{Function: "main.main"}
+	assert.True(t, slog.CallerPkgPrefix("main").Allow(r))
+
+	// combinators
+	assert.False(t, slog.Not(slog.FieldExists("amount")).Allow(r))
+	assert.True(t, slog.And().Allow(r))
+	assert.True(t, slog.And(slog.FieldExists("amount"), slog.ChannelFilter("pay.*")).Allow(r))
+	assert.False(t, slog.And(slog.FieldExists("amount"), slog.ChannelFilter("user")).Allow(r))
+	assert.False(t, slog.Or().Allow(r))
+	assert.True(t, slog.Or(slog.FieldExists("not-exists"), slog.ChannelFilter("pay.*")).Allow(r))
+}
+
+func TestLogger_AddFilter(t *testing.T) {
+	l, rh := slogtest.NewLogger(t)
+	// suppress the noisy channel, but keep its errors
+	l.AddFilter(slog.Or(
+		slog.Not(slog.ChannelFilter("gorm")),
+		slog.FieldExists("error"),
+	))
+
+	processed := 0
+	l.AddProcessor(slog.ProcessorFunc(func(r *slog.Record) {
+		processed++
+	}))
+
+	// the named logger use the name as channel
+	gl := slog.NewRegistry(l).Get("gorm")
+	l.Info("info message")
+	gl.Info("gorm message")
+	gl.WithField("error", "timeout").Warn("gorm slow query")
+	gl.Build(slog.InfoLevel).Str("error", "typed").Msg("typed message")
+	assert.Eq(t, []string{"info message", "gorm slow query", "typed message"}, rh.Messages())
+	// the filters run before the processors
+	assert.Eq(t, 3, processed)
+
+	// the panic, fatal records are never dropped
+	l.AddFilters(slog.MessageMatch(`^keep`))
+	l.Info("info message2")
+	l.Panic("panic message")
+	rh.AssertLogged(t, slog.PanicLevel, "panic message")
+	rh.AssertNotLogged(t, slog.InfoLevel, "info message2")
+
+	// reset
+	l.ResetFilters()
+	l.Info("info message3")
+	gl.Info("gorm message3")
+	rh.AssertLogged(t, slog.InfoLevel, "info message3")
+	rh.AssertLogged(t, slog.InfoLevel, "gorm message3")
+
+	// async mode: the filtered records are not queued
+	l.SetFilters([]slog.Filter{slog.Not(slog.ChannelFilter("gorm"))})
+	l.EnableAsync()
+	gl.Info("gorm message4")
+	l.Info("info message4")
+	assert.NoErr(t, l.Flush())
+	rh.AssertLogged(t, slog.InfoLevel, "info message4")
+	rh.AssertNotLogged(t, slog.InfoLevel, "gorm message4")
+	assert.Eq(t, uint64(1), l.AsyncStats().Handled)
+	assert.NoErr(t, l.Close())
+}
@@ -1,6 +1,11 @@
 package slog
 
-import "runtime"
+import (
+	"io"
+	"runtime"
+
+	"github.com/valyala/bytebufferpool"
+)
 
 //
 // Formatter interface
@@ -12,6 +17,43 @@ type Formatter interface {
 	Format(record *Record) ([]byte, error)
 }
 
+// AppendFormatter an optional interface for the Formatter, format record and append to dst.
+//
+// It's used by FormatTo() for write the log without copy the formatted bytes.
+type AppendFormatter interface {
+	AppendFormat(dst []byte, r *Record) ([]byte, error)
+}
+
+var writePool bytebufferpool.Pool
+
+// FormatTo format the record by formatter and write to w.
+//
+// If the formatter implements AppendFormatter, will format to a pooled buffer,
+// avoid allocating the result bytes. NOTE: w must not retain the written bytes.
+func FormatTo(w io.Writer, f Formatter, r *Record) error {
+	af, ok := f.(AppendFormatter)
+	if !ok {
+		bts, err := f.Format(r)
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(bts)
+		return err
+	}
+
+	buf := writePool.Get()
+	defer writePool.Put(buf)
+
+	var err error
+	if buf.B, err = af.AppendFormat(buf.B, r); err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.B)
+	return err
+}
+
 // FormatterFunc wrapper definition
 type FormatterFunc func(r *Record) ([]byte, error)
 
@@ -2,6 +2,11 @@ package slog
 
 import (
 	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+	"unicode/utf8"
 
 	"github.com/valyala/bytebufferpool"
 )
@@ -37,6 +42,8 @@ type JSONFormatter struct {
 	// - item: `"field" : "output name"`
 	//
 	// eg: {"message": "msg"} export field will display "msg"
+	//
+	// TIP: also can rename the custom Record.Fields. eg: {"trace_id": "traceId"}
 	Aliases StringMap
 
 	// PrettyPrint will indent all JSON logs
@@ -76,10 +83,161 @@ func (f *JSONFormatter) AddField(name string) *JSONFormatter {
 var jsonPool bytebufferpool.Pool
 
 // Format a log record to JSON bytes
+//
+// The typed fields of the record(see Record.Build) are appended after other fields, keep the added order.
+// The typed field will be prefixed with "fields." if the key is used by other fields.
 func (f *JSONFormatter) Format(r *Record) ([]byte, error) {
+	if f.PrettyPrint {
+		return f.formatByMap(r)
+	}
+
+	buf := jsonPool.Get()
+	defer jsonPool.Put(buf)
+
+	var err error
+	buf.B, err = f.AppendFormat(buf.B, r)
+	// NOTE: return an independent copy. buf is returned to the shared pool on
+	// defer, so returning buf.Bytes() directly would race with a concurrent
+	// Format() that reuses the same buffer. See TextFormatter.Format for detail.
+	return append([]byte(nil), buf.B...), err
+}
+
+// AppendFormat format a log record to JSON and append to dst. implements the AppendFormatter
+func (f *JSONFormatter) AppendFormat(dst []byte, r *Record) ([]byte, error) {
+	if f.PrettyPrint {
+		bs, err := f.formatByMap(r)
+		return append(dst, bs...), err
+	}
+	return f.appendRecord(dst, r)
+}
+
+// an entry of the JSON object. field is built-in field name, or empty for custom field.
+type jsonEntry struct {
+	key   string
+	field string
+	val   any
+}
+
+// appendRecord encode the record to JSON without map. the keys are sorted, same as encoding/json.
+func (f *JSONFormatter) appendRecord(b []byte, r *Record) ([]byte, error) {
+	var arr [16]jsonEntry
+	entries := arr[:0]
+
+	for _, field := range f.Fields {
+		switch field {
+		case FieldKeyDatetime, FieldKeyTimestamp, FieldKeyLevel, FieldKeyChannel, FieldKeyMessage, FieldKeyData, FieldKeyExtra:
+		case FieldKeyCaller:
+			if r.Caller == nil {
+				continue
+			}
+		default:
+			continue
+		}
+
+		outName, ok := f.Aliases[field]
+		if !ok {
+			outName = field
+		}
+		if !hasJSONEntry(entries, outName) {
+			entries = append(entries, jsonEntry{key: outName, field: field})
+		}
+	}
+
+	// captured call stack
+	if len(r.Stack) > 0 {
+		outName, ok := f.Aliases[FieldKeyStacktrace]
+		if !ok {
+			outName = FieldKeyStacktrace
+		}
+		if !hasJSONEntry(entries, outName) {
+			entries = append(entries, jsonEntry{key: outName, field: FieldKeyStacktrace})
+		}
+	}
+
+	// exported custom record fields
+	for field, value := range r.Fields {
+		fieldKey, ok := f.Aliases[field]
+		if !ok {
+			fieldKey = field
+		}
+		if hasJSONEntry(entries, fieldKey) {
+			fieldKey = "fields." + fieldKey
+		}
+		entries = append(entries, jsonEntry{key: fieldKey, val: value})
+	}
+
+	// sort by key, the number of entries is small.
+	for i := 1; i < len(entries); i++ {
+		for j := i; j > 0 && entries[j].key < entries[j-1].key; j-- {
+			entries[j], entries[j-1] = entries[j-1], entries[j]
+		}
+	}
+
+	var err, err1 error
+	b = append(b, '{')
+	for i, e := range entries {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, e.key)
+		b = append(b, ':')
+
+		switch e.field {
+		case FieldKeyDatetime:
+			b = appendJSONTime(b, r.Time, f.TimeFormat)
+		case FieldKeyTimestamp:
+			b = appendJSONString(b, r.timestamp())
+		case FieldKeyCaller:
+			b = appendJSONString(b, formatCaller(r.Caller, r.CallerFlag, f.CallerFormatFunc))
+		case FieldKeyLevel:
+			b = appendJSONString(b, r.LevelName())
+		case FieldKeyChannel:
+			b = appendJSONString(b, r.Channel)
+		case FieldKeyMessage:
+			b = appendJSONString(b, r.Message)
+		case FieldKeyData:
+			b, err1 = appendJSONValue(b, r.Data)
+		case FieldKeyExtra:
+			b, err1 = appendJSONValue(b, r.Extra)
+		case FieldKeyStacktrace:
+			b = appendJSONStack(b, r.Stack)
+		default:
+			b, err1 = appendJSONValue(b, e.val)
+		}
+
+		if err1 != nil {
+			err = err1
+		}
+	}
+
+	// typed fields
+	if len(r.typed) > 0 {
+		if len(entries) > 0 {
+			b = append(b, ',')
+		}
+		b, err1 = appendJSONFields(b, r.typed, false, f.TimeFormat, entries)
+		if err1 != nil {
+			err = err1
+		}
+	}
+
+	b = append(b, '}', '\n')
+	return b, err
+}
+
+func hasJSONEntry(entries []jsonEntry, key string) bool {
+	for _, e := range entries {
+		if e.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// formatByMap format the record by map and encoding/json. used on PrettyPrint=true
+func (f *JSONFormatter) formatByMap(r *Record) ([]byte, error) {
 	logData := make(M, len(f.Fields))
 
-	// TODO perf: use buf write build JSON string.
 	for _, field := range f.Fields {
 		outName, ok := f.Aliases[field]
 		if !ok {
@@ -100,30 +258,50 @@ func (f *JSONFormatter) Format(r *Record) ([]byte, error) {
 		case field == FieldKeyMessage:
 			logData[outName] = r.Message
 		case field == FieldKeyData:
-			logData[outName] = r.Data
+			logData[outName], _ = toJSONErrorValue(r.Data)
 		case field == FieldKeyExtra:
-			logData[outName] = r.Extra
+			logData[outName], _ = toJSONErrorValue(r.Extra)
 			// default:
 			// 	logData[outName] = r.Fields[field]
 		}
 	}
 
+	// captured call stack
+	if len(r.Stack) > 0 {
+		outName, ok := f.Aliases[FieldKeyStacktrace]
+		if !ok {
+			outName = FieldKeyStacktrace
+		}
+		if _, has := logData[outName]; !has {
+			logData[outName] = r.Stack
+		}
+	}
+
 	// exported custom record fields
 	for field, value := range r.Fields {
-		fieldKey := field
-		if _, has := logData[field]; has {
-			fieldKey = "fields." + field
+		fieldKey, ok := f.Aliases[field]
+		if !ok {
+			fieldKey = field
+		}
+		if _, has := logData[fieldKey]; has {
+			fieldKey = "fields." + fieldKey
+		}
+		logData[fieldKey], _ = toJSONErrorValue(value)
+	}
+
+	// typed fields
+	for i := 0; i < len(r.typed); i++ {
+		key := r.typed[i].Key
+		if _, has := logData[key]; has {
+			key = "fields." + key
 		}
-		logData[fieldKey] = value
+
+		logData[key], _ = toJSONErrorValue(formatJSONTimes(fieldValue(r.typed, i), f.TimeFormat))
+		i += r.typed[i].Span()
 	}
 
-	// sort.Interface()
 	buf := jsonPool.Get()
-	// buf.Reset()
 	defer jsonPool.Put(buf)
-	// buf := r.NewBuffer()
-	// buf.Reset()
-	// buf.Grow(256)
 
 	encoder := json.NewEncoder(buf)
 	if f.PrettyPrint {
@@ -132,8 +310,288 @@ func (f *JSONFormatter) Format(r *Record) ([]byte, error) {
 
 	// has been added newline in Encode().
 	err := encoder.Encode(logData)
-	// NOTE: return an independent copy. buf is returned to the shared pool on
-	// defer, so returning buf.Bytes() directly would race with a concurrent
-	// Format() that reuses the same buffer. See TextFormatter.Format for detail.
 	return append([]byte(nil), buf.Bytes()...), err
 }
+
+// format the time.Time in the typed field value by the layout
+func formatJSONTimes(v any, layout string) any {
+	switch tv := v.(type) {
+	case time.Time:
+		return tv.Format(layout)
+	case M:
+		for k, sv := range tv {
+			tv[k] = formatJSONTimes(sv, layout)
+		}
+	case []any:
+		for i, sv := range tv {
+			tv[i] = formatJSONTimes(sv, layout)
+		}
+	}
+	return v
+}
+
+//
+// ---------------------------------------------------------------------------
+// region JSON encode helpers
+// ---------------------------------------------------------------------------
+//
+
+// appendJSONFields encode typed fields as JSON object members, or array elements on inArray=true.
+// The key is prefixed with "fields." if it is in the taken entries.
+func appendJSONFields(b []byte, fs []Field, inArray bool, timeFormat string, taken []jsonEntry) ([]byte, error) {
+	var err, err1 error
+	for i := 0; i < len(fs); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		fd := &fs[i]
+		if !inArray {
+			if hasJSONEntry(taken, fd.Key) {
+				b = appendJSONString(b, "fields."+fd.Key)
+			} else {
+				b = appendJSONString(b, fd.Key)
+			}
+			b = append(b, ':')
+		}
+
+		switch fd.Kind {
+		case FieldKindString:
+			b = appendJSONString(b, fd.str)
+		case FieldKindInt:
+			b = strconv.AppendInt(b, fd.Int64(), 10)
+		case FieldKindUint:
+			b = strconv.AppendUint(b, fd.num, 10)
+		case FieldKindFloat:
+			b = appendJSONFloat(b, fd.Float64())
+		case FieldKindBool:
+			b = strconv.AppendBool(b, fd.Bool())
+		case FieldKindDuration:
+			b = appendJSONString(b, fd.Duration().String())
+		case FieldKindTime:
+			b = appendJSONTime(b, fd.Time(), timeFormat)
+		case FieldKindError:
+			if e := fd.Err(); e != nil {
+				b, err1 = appendJSONValue(b, e)
+			} else {
+				b = append(b, "null"...)
+			}
+		case FieldKindBytes:
+			b = appendJSONString(b, string(fd.Bytes()))
+		case FieldKindDict, FieldKindArray:
+			sub := fs[i+1 : i+1+fd.Span()]
+			isArr := fd.Kind == FieldKindArray
+			if isArr {
+				b = append(b, '[')
+			} else {
+				b = append(b, '{')
+			}
+
+			b, err1 = appendJSONFields(b, sub, isArr, timeFormat, nil)
+			if isArr {
+				b = append(b, ']')
+			} else {
+				b = append(b, '}')
+			}
+			i += len(sub)
+		default: // FieldKindAny
+			b, err1 = appendJSONValue(b, fd.val)
+		}
+
+		if err1 != nil {
+			err = err1
+		}
+	}
+	return b, err
+}
+
+// appendJSONValue encode value to JSON. the common types are encoded without encoding/json
+func appendJSONValue(b []byte, v any) ([]byte, error) {
+	switch val := v.(type) {
+	case nil:
+		return append(b, "null"...), nil
+	case string:
+		return appendJSONString(b, val), nil
+	case bool:
+		return strconv.AppendBool(b, val), nil
+	case int:
+		return strconv.AppendInt(b, int64(val), 10), nil
+	case int64:
+		return strconv.AppendInt(b, val, 10), nil
+	case int32:
+		return strconv.AppendInt(b, int64(val), 10), nil
+	case uint:
+		return strconv.AppendUint(b, uint64(val), 10), nil
+	case uint64:
+		return strconv.AppendUint(b, val, 10), nil
+	case uint32:
+		return strconv.AppendUint(b, uint64(val), 10), nil
+	case M:
+		if val == nil {
+			return append(b, "null"...), nil
+		}
+		return appendJSONMap(b, val)
+	case map[string]any:
+		if val == nil {
+			return append(b, "null"...), nil
+		}
+		return appendJSONMap(b, val)
+	case json.Marshaler:
+		// use the custom MarshalJSON(), even if it is an error.
+	case error:
+		return appendJSONErrorChain(b, ErrorChain(val)), nil
+	}
+
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return append(b, "null"...), err
+	}
+	return append(b, bs...), nil
+}
+
+// appendJSONMap encode map with sorted keys, same as encoding/json. the error values are encoded by ErrorChain()
+func appendJSONMap(b []byte, m map[string]any) ([]byte, error) {
+	if len(m) == 0 {
+		return append(b, '{', '}'), nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var err, err1 error
+	b = append(b, '{')
+	for i, k := range keys {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, k)
+		b = append(b, ':')
+		if b, err1 = appendJSONValue(b, m[k]); err1 != nil {
+			err = err1
+		}
+	}
+	return append(b, '}'), err
+}
+
+// appendJSONFloat encode float like encoding/json. the NaN and Inf are encoded as string.
+func appendJSONFloat(b []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return appendJSONString(b, strconv.FormatFloat(f, 'g', -1, 64))
+	}
+
+	abs := math.Abs(f)
+	fmtByte := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		fmtByte = 'e'
+	}
+
+	b = strconv.AppendFloat(b, f, fmtByte, -1, 64)
+	if fmtByte == 'e' {
+		// clean up e-09 to e-9
+		n := len(b)
+		if n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
+
+// appendJSONTime encode time with layout as JSON string
+func appendJSONTime(b []byte, t time.Time, layout string) []byte {
+	b = append(b, '"')
+	start := len(b)
+	b = t.AppendFormat(b, layout)
+	// the layout contains special chars, need escape.
+	if jsonNeedEscape(b[start:]) {
+		s := string(b[start:])
+		return appendJSONString(b[:start-1], s)
+	}
+	return append(b, '"')
+}
+
+func jsonNeedEscape(bs []byte) bool {
+	for _, c := range bs {
+		if c < 0x20 || c >= utf8.RuneSelf || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' {
+			return true
+		}
+	}
+	return false
+}
+
+const hexChars = "0123456789abcdef"
+
+// appendJSONString encode string as JSON string, same as encoding/json with HTML escape.
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+
+			b = append(b, s[start:i]...)
+			switch c {
+			case '\\', '"':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				// control chars and <, >, &
+				b = append(b, '\\', 'u', '0', '0', hexChars[c>>4], hexChars[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		c, size := utf8.DecodeRuneInString(s[i:])
+		if c == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+
+		// U+2028 is LINE SEPARATOR, U+2029 is PARAGRAPH SEPARATOR.
+		if c == '\u2028' || c == '\u2029' {
+			b = append(b, s[start:i]...)
+			b = append(b, '\\', 'u', '2', '0', '2', hexChars[c&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
+
+// append the stack frames as JSON array. eg: [{"function":"main.main","file":"/path/to/main.go","line":23}]
+func appendJSONStack(b []byte, stack []StackFrame) []byte {
+	b = append(b, '[')
+	for i, sf := range stack {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = append(b, `{"function":`...)
+		b = appendJSONString(b, sf.Function)
+		b = append(b, `,"file":`...)
+		b = appendJSONString(b, sf.File)
+		b = append(b, `,"line":`...)
+		b = strconv.AppendInt(b, int64(sf.Line), 10)
+		b = append(b, '}')
+	}
+	return append(b, ']')
+}
@@ -1,6 +1,10 @@
 package slog
 
 import (
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/gookit/color"
 	"github.com/gookit/goutil/arrutil"
 	"github.com/valyala/bytebufferpool"
@@ -143,10 +147,7 @@ func (f *TextFormatter) Fields() []string {
 var textPool bytebufferpool.Pool
 
 // Format a log record
-//
-//goland:noinspection GoUnhandledErrorResult
 func (f *TextFormatter) Format(r *Record) ([]byte, error) {
-	f.beforeFormat()
 	buf := textPool.Get()
 	// NOTE: must return an independent copy of the bytes. The buffer is put
 	// back to the pool on return, and the pool is shared across loggers/handlers.
@@ -154,56 +155,79 @@ func (f *TextFormatter) Format(r *Record) ([]byte, error) {
 	// same buffer and overwrite the bytes while the handler is still writing them.
 	defer textPool.Put(buf)
 
+	buf.B, _ = f.AppendFormat(buf.B, r)
+	return append([]byte(nil), buf.B...), nil
+}
+
+// AppendFormat format a log record and append to dst. implements the AppendFormatter
+func (f *TextFormatter) AppendFormat(b []byte, r *Record) ([]byte, error) {
+	f.beforeFormat()
+
 	// record formatted custom fields
 	var formattedFields []string
+	// the stack is rendered by {{stacktrace}} in template
+	var stackRendered bool
 
 	for _, field := range f.fields {
 		// is not field name. eg: "}}] "
 		if field[0] < 'a' || field[0] > 'z' {
 			// remove left "}}"
 			if len(field) > 1 && field[0:2] == "}}" {
-				buf.WriteString(field[2:])
+				b = append(b, field[2:]...)
 			} else {
-				buf.WriteString(field)
+				b = append(b, field...)
 			}
 			continue
 		}
 
 		switch {
 		case field == FieldKeyDatetime:
-			buf.B = r.Time.AppendFormat(buf.B, f.TimeFormat)
+			b = r.Time.AppendFormat(b, f.TimeFormat)
 		case field == FieldKeyTimestamp:
-			buf.WriteString(r.timestamp())
+			b = append(b, r.timestamp()...)
 		case field == FieldKeyCaller:
 			// render empty when caller is not reported (ReportCaller=false),
 			// instead of falling through and printing the literal "caller".
 			if r.Caller != nil {
-				buf.WriteString(formatCaller(r.Caller, r.CallerFlag, f.CallerFormatFunc))
+				b = append(b, formatCaller(r.Caller, r.CallerFlag, f.CallerFormatFunc)...)
 			}
 		case field == FieldKeyLevel:
-			buf.WriteString(f.renderColorText(field, r.LevelName(), r.Level))
+			b = append(b, f.renderColorText(field, r.LevelName(), r.Level)...)
 		case field == FieldKeyChannel:
-			buf.WriteString(r.Channel)
+			b = append(b, r.Channel...)
 		case field == FieldKeyMessage:
-			buf.WriteString(f.renderColorText(field, r.Message, r.Level))
+			b = append(b, f.renderColorText(field, r.Message, r.Level)...)
+			// typed fields. eg: " key=value key1=value1"
+			if len(r.typed) > 0 {
+				b = f.appendFields(b, r.typed, false)
+			}
 		case field == FieldKeyData:
 			if f.FullDisplay || len(r.Data) > 0 {
-				buf.WriteString(f.EncodeFunc(r.Data))
+				b = append(b, f.EncodeFunc(r.Data)...)
 			}
 		case field == FieldKeyExtra:
 			if f.FullDisplay || len(r.Extra) > 0 {
-				buf.WriteString(f.EncodeFunc(r.Extra))
+				b = append(b, f.EncodeFunc(r.Extra)...)
 			}
+		case field == FieldKeyStacktrace:
+			stackRendered = true
+			b = appendTextStack(b, r.Stack)
 		default:
 			if _, ok := r.Fields[field]; ok {
 				formattedFields = append(formattedFields, field)
-				buf.WriteString(f.EncodeFunc(r.Fields[field]))
+				b = append(b, f.EncodeFunc(r.Fields[field])...)
 			} else {
-				buf.WriteString(field)
+				b = append(b, field...)
 			}
 		}
 	}
 
+	// the error field will be rendered as error chain after the log line.
+	errInTpl := arrutil.StringsContains(formattedFields, FieldKeyError)
+	if _, ok := r.Fields[FieldKeyError].(error); ok && !errInTpl {
+		formattedFields = append(formattedFields, FieldKeyError)
+	}
+
 	// UP: check not configured fields in template.
 	if fLen := len(r.Fields); fLen > 0 && fLen != len(formattedFields) {
 		unformattedFields := make(map[string]any)
@@ -212,12 +236,143 @@ func (f *TextFormatter) Format(r *Record) ([]byte, error) {
 				unformattedFields[k] = v
 			}
 		}
-		buf.WriteString("UN-CONFIGURED FIELDS: ")
-		buf.WriteString(f.EncodeFunc(unformattedFields))
-		buf.WriteByte('\n')
+		b = append(b, "UN-CONFIGURED FIELDS: "...)
+		b = append(b, f.EncodeFunc(unformattedFields)...)
+		b = append(b, '\n')
 	}
 
-	return append([]byte(nil), buf.B...), nil
+	// render the error chain and stack after the log line, if not in the template.
+	errChain := textErrorChain(r, errInTpl)
+	if len(errChain) > 0 || (!stackRendered && len(r.Stack) > 0) {
+		lfEnd := len(b) > 0 && b[len(b)-1] == '\n'
+		if lfEnd {
+			b = b[:len(b)-1]
+		}
+
+		b = appendTextErrorChain(b, errChain, "\t", "error: ")
+		if !stackRendered {
+			b = appendTextStack(b, r.Stack)
+		}
+		if lfEnd {
+			b = append(b, '\n')
+		}
+	}
+	return b, nil
+}
+
+// get the error chain of the FieldKeyError for render after the log line. returns nil if not need.
+//
+// The chain will be rendered if it has more info than err.Error(), or the error is not displayed.
+func textErrorChain(r *Record, errInTpl bool) []ErrorEntry {
+	err, displayed := r.Fields[FieldKeyError].(error)
+	if displayed {
+		displayed = errInTpl
+	} else {
+		// the typed error field is displayed after message
+		for i := 0; i < len(r.typed); i++ {
+			if fd := &r.typed[i]; fd.Key == FieldKeyError && fd.Kind == FieldKindError {
+				err, displayed = fd.Err(), true
+				break
+			}
+			i += r.typed[i].Span()
+		}
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	chain := ErrorChain(err)
+	if isDetailedChain(chain) || (!displayed && r.Message != err.Error()) {
+		return chain
+	}
+	return nil
+}
+
+// append the stack frames as indented multi lines. eg:
+//
+//	"\n\tmain.main()\n\t\t/path/to/main.go:23"
+func appendTextStack(b []byte, stack []StackFrame) []byte {
+	for _, sf := range stack {
+		b = append(b, "\n\t"...)
+		b = append(b, sf.Function...)
+		b = append(b, "()\n\t\t"...)
+		b = append(b, sf.File...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(sf.Line), 10)
+	}
+	return b
+}
+
+// appendFields append typed fields as " key=value", or " value" on inArray=true.
+func (f *TextFormatter) appendFields(b []byte, fs []Field, inArray bool) []byte {
+	for i := 0; i < len(fs); i++ {
+		fd := &fs[i]
+		if i > 0 || !inArray {
+			b = append(b, ' ')
+		}
+		if !inArray {
+			b = append(b, fd.Key...)
+			b = append(b, '=')
+		}
+
+		switch fd.Kind {
+		case FieldKindString:
+			b = appendTextString(b, fd.str)
+		case FieldKindInt:
+			b = strconv.AppendInt(b, fd.Int64(), 10)
+		case FieldKindUint:
+			b = strconv.AppendUint(b, fd.num, 10)
+		case FieldKindFloat:
+			b = strconv.AppendFloat(b, fd.Float64(), 'g', -1, 64)
+		case FieldKindBool:
+			b = strconv.AppendBool(b, fd.Bool())
+		case FieldKindDuration:
+			b = append(b, fd.Duration().String()...)
+		case FieldKindTime:
+			b = fd.Time().AppendFormat(b, f.TimeFormat)
+		case FieldKindError:
+			if e := fd.Err(); e != nil {
+				b = appendTextString(b, e.Error())
+			} else {
+				b = append(b, "<nil>"...)
+			}
+		case FieldKindBytes:
+			b = appendTextString(b, string(fd.Bytes()))
+		case FieldKindDict:
+			b = append(b, '{')
+			start := len(b)
+			b = f.appendFields(b, fs[i+1:i+1+fd.Span()], false)
+			// remove the first space
+			if len(b) > start {
+				b = append(b[:start], b[start+1:]...)
+			}
+			b = append(b, '}')
+			i += fd.Span()
+		case FieldKindArray:
+			b = append(b, '[')
+			b = f.appendFields(b, fs[i+1:i+1+fd.Span()], true)
+			b = append(b, ']')
+			i += fd.Span()
+		default: // FieldKindAny
+			b = append(b, f.EncodeFunc(fd.val)...)
+		}
+	}
+	return b
+}
+
+// append string, will quote it if contains space, quote, equal sign or control chars.
+func appendTextString(b []byte, s string) []byte {
+	if s == "" {
+		return append(b, `""`...)
+	}
+
+	for _, c := range s {
+		if c <= ' ' || c == '"' || c == '=' || c == utf8.RuneError || !unicode.IsPrint(c) {
+			return strconv.AppendQuote(b, s)
+		}
+	}
+	return append(b, s...)
 }
 
 func (f *TextFormatter) beforeFormat() {
@@ -8,6 +8,7 @@ require (
 	github.com/gookit/gsr v0.1.1
 	github.com/gookit/rotatefile v0.3.0
 	github.com/valyala/bytebufferpool v1.0.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -53,6 +53,8 @@ type LevelWithFormatter struct {
 	FormattableTrait
 	// Level max for logging messages. if current level <= Level will log messages
 	Level Level
+	// LevelVar for change the max level at runtime. if not nil, will use it instead of Level.
+	LevelVar *LevelVar
 }
 
 // NewLvFormatter create new LevelWithFormatter instance
@@ -60,13 +62,30 @@ func NewLvFormatter(maxLv Level) *LevelWithFormatter {
 	return &LevelWithFormatter{Level: maxLv}
 }
 
+// NewLvVarFormatter create new LevelWithFormatter instance with LevelVar
+func NewLvVarFormatter(lv *LevelVar) *LevelWithFormatter {
+	return &LevelWithFormatter{LevelVar: lv}
+}
+
 // SetMaxLevel set max level for logging messages
 func (h *LevelWithFormatter) SetMaxLevel(maxLv Level) {
+	if h.LevelVar != nil {
+		h.LevelVar.Set(maxLv)
+		return
+	}
 	h.Level = maxLv
 }
 
+// SetLevelVar set the LevelVar for change the max level at runtime
+func (h *LevelWithFormatter) SetLevelVar(lv *LevelVar) {
+	h.LevelVar = lv
+}
+
 // IsHandling Check if the current level can be handling
 func (h *LevelWithFormatter) IsHandling(level Level) bool {
+	if h.LevelVar != nil {
+		return h.LevelVar.ShouldHandling(level)
+	}
 	return h.Level.ShouldHandling(level)
 }
 
@@ -172,6 +191,8 @@ type LevelHandling struct {
 	lvMode LevelMode
 	// max level for a log message. if the current level <= Level will log a message
 	maxLevel Level
+	// max level variable, will use it instead of maxLevel if not nil.
+	levelVar *LevelVar
 	// levels limit for log message
 	levels []Level
 }
@@ -179,9 +200,19 @@ type LevelHandling struct {
 // SetMaxLevel set max level for a log message
 func (h *LevelHandling) SetMaxLevel(maxLv Level) {
 	h.lvMode = LevelModeMax
+	if h.levelVar != nil {
+		h.levelVar.Set(maxLv)
+		return
+	}
 	h.maxLevel = maxLv
 }
 
+// SetLevelVar set the LevelVar for change the max level at runtime. will use LevelModeMax
+func (h *LevelHandling) SetLevelVar(lv *LevelVar) {
+	h.lvMode = LevelModeMax
+	h.levelVar = lv
+}
+
 // SetLimitLevels set limit levels for log message
 func (h *LevelHandling) SetLimitLevels(levels []Level) {
 	h.lvMode = LevelModeList
@@ -191,6 +222,9 @@ func (h *LevelHandling) SetLimitLevels(levels []Level) {
 // IsHandling Check if the current level can be handling
 func (h *LevelHandling) IsHandling(level Level) bool {
 	if h.lvMode == LevelModeMax {
+		if h.levelVar != nil {
+			return h.levelVar.ShouldHandling(level)
+		}
 		return h.maxLevel.ShouldHandling(level)
 	}
 
@@ -215,6 +249,13 @@ func NewMaxLevelFormatting(maxLevel Level) *LevelFormatting {
 	return lf
 }
 
+// NewLevelVarFormatting create new instance with LevelVar
+func NewLevelVarFormatting(lv *LevelVar) *LevelFormatting {
+	lf := &LevelFormatting{}
+	lf.SetLevelVar(lv)
+	return lf
+}
+
 // NewLevelsFormatting create new instance with levels
 func NewLevelsFormatting(levels []Level) *LevelFormatting {
 	lf := &LevelFormatting{}
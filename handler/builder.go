@@ -64,6 +64,13 @@ func (b *Builder) WithLogLevel(level slog.Level) *Builder {
 	return b
 }
 
+// WithLevelVar setting max log level by LevelVar
+func (b *Builder) WithLevelVar(lv *slog.LevelVar) *Builder {
+	b.LevelVar = lv
+	b.LevelMode = slog.LevelModeMax
+	return b
+}
+
 // WithLogLevels setting
 func (b *Builder) WithLogLevels(levels []slog.Level) *Builder {
 	b.Levels = levels
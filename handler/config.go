@@ -48,6 +48,11 @@ type Config struct {
 	// Levels list for writing. valid on LevelMode = LevelModeList
 	Levels []slog.Level `json:"levels" yaml:"levels"`
 
+	// LevelVar for change the max level at runtime. valid on LevelMode = LevelModeValue
+	//
+	// If not nil, will use it instead of Level. it can be shared by multiple handlers.
+	LevelVar *slog.LevelVar `json:"-" yaml:"-"`
+
 	// UseJSON for format logs
 	UseJSON bool `json:"use_json" yaml:"use_json"`
 
@@ -135,6 +140,9 @@ func (c *Config) WithConfigFn(fns ...ConfigFn) *Config {
 
 func (c *Config) newLevelFormattable() slog.LevelFormattable {
 	if c.LevelMode == LevelModeValue {
+		if c.LevelVar != nil {
+			return slog.NewLvVarFormatter(c.LevelVar)
+		}
 		return slog.NewLvFormatter(c.Level)
 	}
 	return slog.NewLvsFormatter(c.Levels)
@@ -269,6 +277,14 @@ func WithLogLevel(level slog.Level) ConfigFn {
 	}
 }
 
+// WithLevelVar setting max log level by LevelVar, can change the level at runtime.
+func WithLevelVar(lv *slog.LevelVar) ConfigFn {
+	return func(c *Config) {
+		c.LevelVar = lv
+		c.LevelMode = LevelModeValue
+	}
+}
+
 // WithLevelName setting max level by name
 func WithLevelName(name string) ConfigFn { return WithLogLevel(slog.LevelByName(name)) }
 
@@ -42,6 +42,11 @@ func TestNewConfig(t *testing.T) {
 
 	c.WithConfigFn(handler.WithLevelNames([]string{"info", "debug"}))
 	assert.Eq(t, []slog.Level{slog.InfoLevel, slog.DebugLevel}, c.Levels)
+
+	lv := slog.NewLevelVar(slog.WarnLevel)
+	c.With(handler.WithLevelVar(lv))
+	assert.Eq(t, handler.LevelModeValue, c.LevelMode)
+	assert.Eq(t, lv, c.LevelVar)
 }
 
 func TestConfig_fromJSON(t *testing.T) {
@@ -163,6 +168,15 @@ func TestNewBuilder(t *testing.T) {
 	h2 := b1.Build()
 	assert.NotNil(t, h2)
 
+	lv := slog.NewLevelVar(slog.ErrorLevel)
+	h3 := handler.NewBuilder().
+		WithOutput(new(bytes.Buffer)).
+		WithLevelVar(lv).
+		Build()
+	assert.False(t, h3.IsHandling(slog.InfoLevel))
+	lv.Set(slog.InfoLevel)
+	assert.True(t, h3.IsHandling(slog.InfoLevel))
+
 	assert.Panics(t, func() {
 		handler.NewBuilder().Build()
 	})
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// there are default options of the DedupHandler
+const (
+	DefaultDedupWindow     = time.Minute
+	DefaultDedupMaxEntries = 1000
+)
+
+// DedupHandler wrap a handler, collapse the repeated records in a time window.
+//
+// The records are fingerprinted by (level, channel, message) and optionally the caller.
+// In each window, only the first occurrence is forwarded, and a summary record
+// "<message> (repeated N times)" will be emitted when the window closes.
+//
+// The windows that are over are closed on handle the next record. All open windows
+// are closed on Flush and Close.
+//
+// NOTE: the Logger calls Flush after each error record, so the repeated error records
+// are only collapsed between the flushes.
+//
+// The Panic and Fatal level records are never deduplicated.
+//
+// Usage:
+//
+//	h := handler.NewDedupHandler(fileHandler, func(h *handler.DedupHandler) {
+//		h.Window = 30 * time.Second
+//	})
+type DedupHandler struct {
+	// Handler the wrapped handler
+	Handler slog.Handler
+	// Window the time window for collapse the repeated records. default is DefaultDedupWindow
+	Window time.Duration
+	// WithCaller add the caller(file:line) to the fingerprint. requires Logger.ReportCaller=true
+	WithCaller bool
+	// MaxEntries max number of the tracked fingerprints, the oldest will be closed on full.
+	// default is DefaultDedupMaxEntries
+	MaxEntries int
+	// TimeClock custom time clock. default is slog.DefaultClockFn
+	TimeClock slog.ClockFn
+
+	mu sync.Mutex
+	// the list is ordered by the window start time, the value is *dedupEntry
+	entries map[dedupKey]*list.Element
+	order   *list.List
+}
+
+// the fingerprint of the record
+type dedupKey struct {
+	level   slog.Level
+	channel string
+	message string
+	caller  string
+}
+
+type dedupEntry struct {
+	key   dedupKey
+	start time.Time
+	// the first record, use for build the summary record
+	first    *slog.Record
+	repeated int
+}
+
+// NewDedupHandler create a new DedupHandler
+func NewDedupHandler(h slog.Handler, fns ...func(h *DedupHandler)) *DedupHandler {
+	dh := &DedupHandler{
+		Handler:    h,
+		Window:     DefaultDedupWindow,
+		MaxEntries: DefaultDedupMaxEntries,
+		TimeClock:  slog.DefaultClockFn,
+		// init
+		entries: make(map[dedupKey]*list.Element),
+		order:   list.New(),
+	}
+
+	for _, fn := range fns {
+		fn(dh)
+	}
+	return dh
+}
+
+// IsHandling check level is enabled by the wrapped handler
+func (h *DedupHandler) IsHandling(level slog.Level) bool {
+	return h.Handler.IsHandling(level)
+}
+
+// Handle log record, the repeated record will be dropped and return nil.
+func (h *DedupHandler) Handle(r *slog.Record) error {
+	now := h.TimeClock.Now()
+
+	h.mu.Lock()
+	summaries := h.closeWindows(now, false)
+	passed := r.Level <= slog.FatalLevel || h.track(r, now, &summaries)
+	h.mu.Unlock()
+
+	err := h.handleSummaries(summaries)
+	if passed {
+		if err1 := h.Handler.Handle(r); err1 != nil {
+			return err1
+		}
+	}
+	return err
+}
+
+// track the record, returns false if it is repeated. h.mu is held.
+func (h *DedupHandler) track(r *slog.Record, now time.Time, summaries *[]*slog.Record) bool {
+	key := dedupKey{level: r.Level, channel: r.Channel, message: r.Message}
+	if h.WithCaller && r.Caller != nil {
+		key.caller = fmt.Sprintf("%s:%d", r.Caller.File, r.Caller.Line)
+	}
+
+	// init on the handler is created without NewDedupHandler()
+	if h.entries == nil {
+		h.entries = make(map[dedupKey]*list.Element)
+		h.order = list.New()
+	}
+
+	if el, ok := h.entries[key]; ok {
+		el.Value.(*dedupEntry).repeated++
+		return false
+	}
+
+	maxEntries := h.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultDedupMaxEntries
+	}
+
+	// close the oldest window on full
+	for len(h.entries) >= maxEntries {
+		if sr := h.closeEntry(h.order.Front(), now); sr != nil {
+			*summaries = append(*summaries, sr)
+		}
+	}
+
+	// the record will be reused by the logger, so keep a copy of it.
+	e := &dedupEntry{key: key, start: now, first: r.Clone()}
+	h.entries[key] = h.order.PushBack(e)
+	return true
+}
+
+// close the windows that are over, or all windows if all=true. returns the summary records. h.mu is held.
+func (h *DedupHandler) closeWindows(now time.Time, all bool) []*slog.Record {
+	if h.order == nil {
+		return nil
+	}
+
+	window := h.window()
+	var summaries []*slog.Record
+	for el := h.order.Front(); el != nil; el = h.order.Front() {
+		if !all && now.Sub(el.Value.(*dedupEntry).start) < window {
+			break
+		}
+		if sr := h.closeEntry(el, now); sr != nil {
+			summaries = append(summaries, sr)
+		}
+	}
+	return summaries
+}
+
+// remove the entry, returns the summary record if it has repeated records. h.mu is held.
+func (h *DedupHandler) closeEntry(el *list.Element, now time.Time) *slog.Record {
+	e := el.Value.(*dedupEntry)
+	h.order.Remove(el)
+	delete(h.entries, e.key)
+
+	if e.repeated == 0 {
+		return nil
+	}
+
+	sr := e.first
+	sr.Time = now
+	if e.repeated == 1 {
+		sr.Message = e.first.Message + " (repeated 1 time)"
+	} else {
+		sr.Message = fmt.Sprintf("%s (repeated %d times)", e.first.Message, e.repeated)
+	}
+	sr.AddValue(slog.FieldKeyRepeated, e.repeated)
+	return sr
+}
+
+func (h *DedupHandler) window() time.Duration {
+	if h.Window > 0 {
+		return h.Window
+	}
+	return DefaultDedupWindow
+}
+
+func (h *DedupHandler) handleSummaries(summaries []*slog.Record) error {
+	var err error
+	for _, sr := range summaries {
+		if err1 := h.Handler.Handle(sr); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+	return err
+}
+
+// Tracked get the number of the tracked fingerprints
+func (h *DedupHandler) Tracked() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.entries)
+}
+
+// Flush close all windows and emit the summaries, then flush the wrapped handler.
+func (h *DedupHandler) Flush() error {
+	if err := h.closeAll(); err != nil {
+		return err
+	}
+	return h.Handler.Flush()
+}
+
+// Close all windows and emit the summaries, then close the wrapped handler.
+func (h *DedupHandler) Close() error {
+	if err := h.closeAll(); err != nil {
+		return err
+	}
+	return h.Handler.Close()
+}
+
+func (h *DedupHandler) closeAll() error {
+	h.mu.Lock()
+	summaries := h.closeWindows(h.TimeClock.Now(), true)
+	h.mu.Unlock()
+
+	return h.handleSummaries(summaries)
+}
@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/slogtest"
+)
+
+func TestDedupHandler(t *testing.T) {
+	clock := slogtest.NewClock(time.Time{})
+	rh := slogtest.NewRecordingHandler()
+	h := handler.NewDedupHandler(rh, func(h *handler.DedupHandler) {
+		h.Window = time.Minute
+		h.TimeClock = clock.ClockFn()
+	})
+	assert.True(t, h.IsHandling(slog.DebugLevel))
+
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+	for i := 0; i < 3; i++ {
+		l.Warn("disk full")
+		l.Info("retry")
+	}
+	l.Notice("disk full")
+	assert.Eq(t, []string{"disk full", "retry", "disk full"}, rh.Messages())
+	assert.Eq(t, 3, h.Tracked())
+
+	// the window closes
+	clock.Advance(time.Minute)
+	l.Warn("disk full")
+	assert.Eq(t, []string{"disk full (repeated 2 times)", "retry (repeated 2 times)", "disk full"}, rh.Messages()[3:])
+	sr := rh.Records()[3]
+	assert.Eq(t, slog.WarnLevel, sr.Level)
+	assert.Eq(t, clock.Now(), sr.Time)
+	slogtest.AssertRecordField(t, sr, slog.FieldKeyRepeated, 2)
+	assert.Eq(t, 1, h.Tracked())
+
+	// flush: emit the summaries of the open windows
+	l.Warn("disk full")
+	assert.NoErr(t, l.Flush())
+	assert.Eq(t, "disk full (repeated 1 time)", rh.Last().Message)
+	assert.Eq(t, 0, h.Tracked())
+
+	// the panic records are never deduplicated
+	l.Panic("panic message")
+	l.Panic("panic message")
+	assert.Len(t, rh.ByMessage("panic message"), 2)
+
+	// close: emit the summaries of the open windows
+	l.Info("retry")
+	l.Info("retry")
+	assert.NoErr(t, l.Close())
+	assert.Eq(t, "retry (repeated 1 time)", rh.Last().Message)
+	assert.Eq(t, 0, h.Tracked())
+	assert.Eq(t, 1, rh.Closed())
+}
+
+func TestDedupHandler_options(t *testing.T) {
+	rh := slogtest.NewRecordingHandler()
+	h := handler.NewDedupHandler(rh, func(h *handler.DedupHandler) {
+		h.WithCaller = true
+		h.MaxEntries = 2
+	})
+
+	l := slog.NewWithHandlers(h)
+	for i := 0; i < 2; i++ {
+		l.Info("message") // call site 1
+		l.Info("message") // call site 2
+	}
+	assert.Eq(t, []string{"message", "message"}, rh.Messages())
+
+	// the oldest entry is closed on full
+	l.Info("other message")
+	assert.Eq(t, []string{"message (repeated 1 time)", "other message"}, rh.Messages()[2:])
+	assert.Eq(t, 2, h.Tracked())
+
+	// the windows are over on handle the next record
+	h.Window = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	l.Info("new message")
+	assert.Eq(t, []string{"message (repeated 1 time)", "new message"}, rh.Messages()[4:])
+	assert.Eq(t, 1, h.Tracked())
+
+	// create without the constructor
+	h = &handler.DedupHandler{Handler: rh}
+	l = slog.NewWithHandlers(h)
+	l.Info("message")
+	l.Info("message")
+	assert.NoErr(t, h.Flush())
+	assert.Eq(t, []string{"message", "message (repeated 1 time)"}, rh.Messages()[6:])
+}
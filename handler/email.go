@@ -19,6 +19,7 @@ type EmailOption struct {
 type EmailHandler struct {
 	NopFlushClose
 	slog.LevelWithFormatter
+	slog.Processable
 	// From the sender email information
 	From EmailOption
 	// ToAddresses email list
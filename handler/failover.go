@@ -0,0 +1,352 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// there are default options of the FailoverHandler
+const (
+	DefaultFailoverMaxRetries       = 2
+	DefaultFailoverRetryBackoff     = 10 * time.Millisecond
+	DefaultFailoverMaxBackoff       = time.Second
+	DefaultFailoverMaxRetryWait     = 100 * time.Millisecond
+	DefaultFailoverFailureThreshold = 3
+	DefaultFailoverProbeInterval    = 30 * time.Second
+)
+
+// ErrFailoverAllFailed error of all handlers of the FailoverHandler are failed
+var ErrFailoverAllFailed = errors.New("slog: failover all handlers failed")
+
+// CircuitState the circuit breaker state of the FailoverHandler
+type CircuitState uint8
+
+// there are circuit breaker states
+const (
+	// CircuitClosed the primary handler is in use
+	CircuitClosed CircuitState = iota
+	// CircuitOpen the primary handler is skipped, use the secondary handlers
+	CircuitOpen
+	// CircuitHalfOpen probing the primary handler
+	CircuitHalfOpen
+)
+
+// String get the state name
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// FailoverHandler wrap a primary handler and the secondary handlers.
+//
+//   - retry the failed Handle of the primary with exponential backoff, then fall over to the secondary handlers in order.
+//   - open the circuit after consecutive failures, the primary will be skipped.
+//   - probe the primary periodically on the circuit is open, close the circuit to fail back on success.
+//
+// Each state change will be reported as a Notice record to the secondary handlers.
+//
+// NOTE: the retries are synchronous. The Logger calls Handle with its lock held, so all
+// logging goroutines are blocked on backoff. The total backoff of a record is capped by
+// MaxRetryWait, keep it small or disable the retries.
+//
+// Usage:
+//
+//	h := handler.NewFailoverHandler(remoteHandler, []slog.Handler{fileHandler}, func(h *handler.FailoverHandler) {
+//		h.ProbeInterval = time.Minute
+//	})
+type FailoverHandler struct {
+	// Primary the primary handler
+	Primary slog.Handler
+	// Secondaries the backup handlers, will be tried in order on the primary failed.
+	Secondaries []slog.Handler
+	// MaxRetries retry times of the primary on handle failed. default is DefaultFailoverMaxRetries
+	//
+	// set to < 0 for disable retry.
+	MaxRetries int
+	// RetryBackoff the initial backoff of the retry, doubled after each retry. default is DefaultFailoverRetryBackoff
+	RetryBackoff time.Duration
+	// MaxBackoff the max backoff of the retry. default is DefaultFailoverMaxBackoff
+	MaxBackoff time.Duration
+	// MaxRetryWait the max total backoff of the retries for a record. default is DefaultFailoverMaxRetryWait
+	MaxRetryWait time.Duration
+	// FailureThreshold number of the consecutive failures to open the circuit. default is DefaultFailoverFailureThreshold
+	FailureThreshold int
+	// ProbeInterval the interval for probe the primary on the circuit is open. default is DefaultFailoverProbeInterval
+	ProbeInterval time.Duration
+	// OnStateChange hook on the circuit state changed
+	OnStateChange func(from, to CircuitState, err error)
+	// TimeClock custom time clock. default is slog.DefaultClockFn
+	TimeClock slog.ClockFn
+
+	mu    sync.Mutex
+	state CircuitState
+	// consecutive failures of the primary
+	failures int
+	// the time of the circuit opened or last probe
+	openedAt time.Time
+}
+
+// NewFailoverHandler create a new FailoverHandler
+func NewFailoverHandler(primary slog.Handler, secondaries []slog.Handler, fns ...func(h *FailoverHandler)) *FailoverHandler {
+	h := &FailoverHandler{
+		Primary:     primary,
+		Secondaries: secondaries,
+		// options
+		MaxRetries:       DefaultFailoverMaxRetries,
+		RetryBackoff:     DefaultFailoverRetryBackoff,
+		MaxBackoff:       DefaultFailoverMaxBackoff,
+		MaxRetryWait:     DefaultFailoverMaxRetryWait,
+		FailureThreshold: DefaultFailoverFailureThreshold,
+		ProbeInterval:    DefaultFailoverProbeInterval,
+		TimeClock:        slog.DefaultClockFn,
+	}
+
+	for _, fn := range fns {
+		fn(h)
+	}
+	return h
+}
+
+// State get the circuit state
+func (h *FailoverHandler) State() CircuitState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state
+}
+
+// Failures get the number of the consecutive failures of the primary
+func (h *FailoverHandler) Failures() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.failures
+}
+
+// IsHandling check level is enabled by the primary or any secondary handler
+func (h *FailoverHandler) IsHandling(level slog.Level) bool {
+	if h.Primary.IsHandling(level) {
+		return true
+	}
+
+	for _, sh := range h.Secondaries {
+		if sh.IsHandling(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle log record. returns error only if the primary and all secondary handlers are failed.
+//
+// The primary is called without the h.mu held, so State() and Failures() are not blocked
+// on retry. Only one record probes the primary on the circuit is half-open, the others
+// go to the secondary handlers.
+func (h *FailoverHandler) Handle(r *slog.Record) error {
+	if !h.Primary.IsHandling(r.Level) {
+		_, err := h.handleSecondaries(r)
+		return err
+	}
+
+	now := h.TimeClock.Now()
+	h.mu.Lock()
+	state := h.state
+	if state == CircuitOpen && now.Sub(h.openedAt) >= h.probeInterval() {
+		state = CircuitHalfOpen
+		h.setState(CircuitHalfOpen, r, nil)
+	} else if state == CircuitHalfOpen {
+		// another record is probing the primary
+		state = CircuitOpen
+	}
+	h.mu.Unlock()
+
+	var err error
+	switch state {
+	case CircuitOpen:
+		// skip the primary
+	case CircuitHalfOpen:
+		// probe the primary, no retry
+		err = h.Primary.Handle(r)
+
+		h.mu.Lock()
+		if err == nil {
+			h.failures = 0
+			h.setState(CircuitClosed, r, nil)
+		} else {
+			h.failures++
+			h.openedAt = h.TimeClock.Now()
+			h.setState(CircuitOpen, r, err)
+		}
+		h.mu.Unlock()
+	default: // CircuitClosed
+		err = h.handlePrimary(r)
+
+		h.mu.Lock()
+		if err == nil {
+			if h.state == CircuitClosed {
+				h.failures = 0
+			}
+		} else {
+			h.failures++
+			if h.state == CircuitClosed && h.failures >= h.failureThreshold() {
+				h.openedAt = now
+				h.setState(CircuitOpen, r, err)
+			}
+		}
+		h.mu.Unlock()
+	}
+
+	if err == nil && state != CircuitOpen {
+		return nil
+	}
+
+	handled, err1 := h.handleSecondaries(r)
+	if handled {
+		return nil
+	}
+	if err == nil {
+		err = err1
+	}
+	if err == nil {
+		// the primary is skipped and no secondary handles the level
+		return ErrFailoverAllFailed
+	}
+	return fmt.Errorf("%w: %v", ErrFailoverAllFailed, err)
+}
+
+// handle the record by the primary, retry with exponential backoff on failed. h.mu is not held.
+func (h *FailoverHandler) handlePrimary(r *slog.Record) error {
+	backoff := h.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultFailoverRetryBackoff
+	}
+	maxBackoff := h.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultFailoverMaxBackoff
+	}
+	maxWait := h.MaxRetryWait
+	if maxWait <= 0 {
+		maxWait = DefaultFailoverMaxRetryWait
+	}
+
+	var waited time.Duration
+	err := h.Primary.Handle(r)
+	for i := 0; err != nil && i < h.MaxRetries; i++ {
+		// stop on the total backoff is over the max wait
+		if waited += backoff; waited > maxWait {
+			break
+		}
+
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		err = h.Primary.Handle(r)
+	}
+	return err
+}
+
+// handle the record by the secondary handlers in order, stop on the first success.
+// returns false if no secondary handled it.
+func (h *FailoverHandler) handleSecondaries(r *slog.Record) (bool, error) {
+	var err error
+	for _, sh := range h.Secondaries {
+		if !sh.IsHandling(r.Level) {
+			continue
+		}
+		if err = sh.Handle(r); err == nil {
+			return true, nil
+		}
+	}
+	return false, err
+}
+
+// set the circuit state and report the change. h.mu is held.
+func (h *FailoverHandler) setState(to CircuitState, r *slog.Record, err error) {
+	from := h.state
+	if from == to {
+		return
+	}
+
+	h.state = to
+	if h.OnStateChange != nil {
+		h.OnStateChange(from, to, err)
+	}
+
+	// the half-open is a transient state, don't report it.
+	if to == CircuitHalfOpen || from == CircuitHalfOpen && to == CircuitOpen {
+		return
+	}
+
+	nr := &slog.Record{
+		Time:    h.TimeClock.Now(),
+		Level:   slog.NoticeLevel,
+		Channel: r.Channel,
+		Message: "slog: failover circuit " + to.String(),
+		Data:    slog.M{"state": to.String(), "failures": h.failures},
+	}
+	if err != nil {
+		nr.Data["error"] = err.Error()
+	}
+	nr.Init(false)
+
+	// report to the other handlers, ignore the errors.
+	for _, sh := range h.Secondaries {
+		if sh.IsHandling(nr.Level) {
+			_ = sh.Handle(nr)
+		}
+	}
+}
+
+func (h *FailoverHandler) failureThreshold() int {
+	if h.FailureThreshold > 0 {
+		return h.FailureThreshold
+	}
+	return DefaultFailoverFailureThreshold
+}
+
+func (h *FailoverHandler) probeInterval() time.Duration {
+	if h.ProbeInterval > 0 {
+		return h.ProbeInterval
+	}
+	return DefaultFailoverProbeInterval
+}
+
+// Flush the primary and secondary handlers. the primary is skipped on the circuit is open.
+func (h *FailoverHandler) Flush() error {
+	h.mu.Lock()
+	open := h.state != CircuitClosed
+	h.mu.Unlock()
+
+	var err error
+	if !open {
+		err = h.Primary.Flush()
+	}
+	for _, sh := range h.Secondaries {
+		if err1 := sh.Flush(); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+	return err
+}
+
+// Close the primary and secondary handlers
+func (h *FailoverHandler) Close() error {
+	err := h.Primary.Close()
+	for _, sh := range h.Secondaries {
+		if err1 := sh.Close(); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package handler_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/slogtest"
+)
+
+// failingHandler fails the Handle on fail=true
+type failingHandler struct {
+	*slogtest.RecordingHandler
+	fail  bool
+	calls int
+}
+
+func (h *failingHandler) Handle(r *slog.Record) error {
+	h.calls++
+	if h.fail {
+		return errors.New("handler is down")
+	}
+	return h.RecordingHandler.Handle(r)
+}
+
+func TestFailoverHandler(t *testing.T) {
+	clock := slogtest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	primary := &failingHandler{RecordingHandler: slogtest.NewRecordingHandler()}
+	backup := slogtest.NewRecordingHandler()
+
+	var changes []string
+	h := handler.NewFailoverHandler(primary, []slog.Handler{backup}, func(h *handler.FailoverHandler) {
+		h.MaxRetries = 2
+		h.RetryBackoff = time.Microsecond
+		h.FailureThreshold = 2
+		h.ProbeInterval = time.Minute
+		h.TimeClock = clock.ClockFn()
+		h.OnStateChange = func(from, to handler.CircuitState, err error) {
+			changes = append(changes, from.String()+"->"+to.String())
+		}
+	})
+	assert.True(t, h.IsHandling(slog.DebugLevel))
+	assert.Eq(t, handler.CircuitClosed, h.State())
+
+	l := slog.NewWithHandlers(h)
+	l.Info("message1")
+	assert.Eq(t, []string{"message1"}, primary.Messages())
+	assert.Eq(t, 0, backup.Len())
+
+	// retry the primary, then fall over to the backup
+	primary.fail = true
+	primary.calls = 0
+	l.Info("message2")
+	assert.Eq(t, 3, primary.calls)
+	assert.Eq(t, []string{"message2"}, backup.Messages())
+	assert.Eq(t, 1, h.Failures())
+	assert.Eq(t, handler.CircuitClosed, h.State())
+
+	// open the circuit, report a notice to the backup
+	l.Info("message3")
+	assert.Eq(t, handler.CircuitOpen, h.State())
+	assert.Eq(t, []string{"message2", "slog: failover circuit open", "message3"}, backup.Messages())
+	nr := backup.Records()[1]
+	assert.Eq(t, slog.NoticeLevel, nr.Level)
+	slogtest.AssertRecordField(t, nr, "state", "open")
+	slogtest.AssertRecordField(t, nr, "error", "handler is down")
+
+	// the primary is skipped on the circuit is open
+	primary.calls = 0
+	l.Info("message4")
+	assert.Eq(t, 0, primary.calls)
+	assert.Eq(t, "message4", backup.Last().Message)
+
+	// probe failed, stay open
+	clock.Advance(time.Minute)
+	l.Info("message5")
+	assert.Eq(t, 1, primary.calls)
+	assert.Eq(t, handler.CircuitOpen, h.State())
+	assert.Eq(t, 3, h.Failures())
+	assert.Eq(t, "message5", backup.Last().Message)
+
+	// not probe again in the interval
+	l.Info("message6")
+	assert.Eq(t, 1, primary.calls)
+
+	// probe success, fail back to the primary
+	primary.fail = false
+	clock.Advance(time.Minute)
+	l.Info("message7")
+	assert.Eq(t, handler.CircuitClosed, h.State())
+	assert.Eq(t, 0, h.Failures())
+	assert.Eq(t, "message7", primary.Last().Message)
+	assert.Eq(t, "slog: failover circuit closed", backup.Last().Message)
+	assert.Eq(t, []string{
+		"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed",
+	}, changes)
+
+	assert.NoErr(t, l.Close())
+	assert.Eq(t, 1, primary.Closed())
+	assert.Eq(t, 1, backup.Closed())
+}
+
+func TestFailoverHandler_allFailed(t *testing.T) {
+	primary := &failingHandler{RecordingHandler: slogtest.NewRecordingHandler(), fail: true}
+	backup1 := &failingHandler{RecordingHandler: slogtest.NewRecordingHandler(), fail: true}
+	backup2 := slogtest.NewRecordingHandler(slog.ErrorLevel)
+
+	h := handler.NewFailoverHandler(primary, []slog.Handler{backup1, backup2}, func(h *handler.FailoverHandler) {
+		h.MaxRetries = -1
+	})
+
+	r := slog.New().Record()
+	r.Level = slog.ErrorLevel
+	r.Message = "error message"
+
+	// try the secondaries in order
+	assert.NoErr(t, h.Handle(r))
+	assert.Eq(t, 1, primary.calls)
+	assert.Eq(t, 1, backup1.calls)
+	assert.Eq(t, []string{"error message"}, backup2.Messages())
+
+	// the backup2 is not handling the info level
+	r.Level = slog.InfoLevel
+	err := h.Handle(r)
+	assert.Err(t, err)
+	assert.True(t, errors.Is(err, handler.ErrFailoverAllFailed))
+	assert.Eq(t, 1, backup2.Len())
+
+	// the primary is skipped on the circuit is open, and no secondary handles the level
+	h = handler.NewFailoverHandler(primary, []slog.Handler{backup2}, func(h *handler.FailoverHandler) {
+		h.MaxRetries = -1
+		h.FailureThreshold = 1
+	})
+	assert.Err(t, h.Handle(r))
+	assert.Eq(t, handler.CircuitOpen, h.State())
+	assert.Eq(t, handler.ErrFailoverAllFailed, h.Handle(r))
+}
+
+// blockingPrimary block the Handle until the release is closed, then fail it
+type blockingPrimary struct {
+	*slogtest.RecordingHandler
+	entered, release chan struct{}
+}
+
+func (h *blockingPrimary) Handle(r *slog.Record) error {
+	close(h.entered)
+	<-h.release
+	return errors.New("handler is down")
+}
+
+func TestFailoverHandler_unlockOnRetry(t *testing.T) {
+	primary := &blockingPrimary{
+		RecordingHandler: slogtest.NewRecordingHandler(),
+		entered:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	backup := slogtest.NewRecordingHandler()
+	h := handler.NewFailoverHandler(primary, []slog.Handler{backup}, func(h *handler.FailoverHandler) {
+		h.MaxRetries = -1
+	})
+
+	done := make(chan error)
+	go func() {
+		r := slog.New().Record()
+		r.Level, r.Message = slog.InfoLevel, "message"
+		done <- h.Handle(r)
+	}()
+	<-primary.entered
+
+	// the lock is not held on calling the primary
+	assert.Eq(t, handler.CircuitClosed, h.State())
+	assert.Eq(t, 0, h.Failures())
+
+	close(primary.release)
+	assert.NoErr(t, <-done)
+	assert.Eq(t, 1, h.Failures())
+	assert.Eq(t, []string{"message"}, backup.Messages())
+}
+
+func TestFailoverHandler_MaxRetryWait(t *testing.T) {
+	primary := &failingHandler{RecordingHandler: slogtest.NewRecordingHandler(), fail: true}
+	// create without the constructor
+	h := &handler.FailoverHandler{
+		Primary:      primary,
+		Secondaries:  []slog.Handler{slogtest.NewRecordingHandler()},
+		MaxRetries:   10,
+		RetryBackoff: time.Millisecond,
+		MaxRetryWait: 5 * time.Millisecond,
+	}
+
+	// backoff: 1, 2, then 4 is over the max wait
+	assert.NoErr(t, h.Handle(slog.New().Record()))
+	assert.Eq(t, 3, primary.calls)
+	assert.Eq(t, 1, h.Failures())
+}
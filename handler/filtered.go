@@ -0,0 +1,51 @@
+package handler
+
+import "github.com/gookit/slog"
+
+// FilteredHandler wrap a handler, only the records allowed by all filters will be handled.
+//
+// Usage:
+//
+//	// route the records of the "gorm" channel to a file
+//	h := handler.Filtered(fileHandler, slog.ChannelFilter("gorm"))
+//	// suppress the debug records of a third-party package
+//	h := handler.Filtered(consoleHandler, slog.Not(slog.And(
+//		slog.CallerPkgPrefix("github.com/some/pkg"),
+//		slog.MessageMatch(`^debug:`),
+//	)))
+type FilteredHandler struct {
+	// Handler the wrapped handler
+	Handler slog.Handler
+	// Filters for the records. the record will be dropped if any filter not allow it.
+	Filters []slog.Filter
+}
+
+// Filtered create a new FilteredHandler, wrap the handler with filters.
+func Filtered(h slog.Handler, filters ...slog.Filter) *FilteredHandler {
+	return &FilteredHandler{Handler: h, Filters: filters}
+}
+
+// AddFilter to the handler
+func (h *FilteredHandler) AddFilter(f slog.Filter) *FilteredHandler {
+	h.Filters = append(h.Filters, f)
+	return h
+}
+
+// IsHandling check level is enabled by the wrapped handler
+func (h *FilteredHandler) IsHandling(level slog.Level) bool {
+	return h.Handler.IsHandling(level)
+}
+
+// Handle log record, the dropped record will return nil.
+func (h *FilteredHandler) Handle(r *slog.Record) error {
+	if slog.AllowRecord(r, h.Filters) {
+		return h.Handler.Handle(r)
+	}
+	return nil
+}
+
+// Flush the wrapped handler
+func (h *FilteredHandler) Flush() error { return h.Handler.Flush() }
+
+// Close the wrapped handler
+func (h *FilteredHandler) Close() error { return h.Handler.Close() }
@@ -0,0 +1,33 @@
+package handler_test
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/byteutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestFiltered(t *testing.T) {
+	buf1 := new(byteutil.Buffer)
+	buf2 := new(byteutil.Buffer)
+
+	// route the "db" channel to buf1, others to buf2
+	h1 := handler.Filtered(handler.NewIOWriter(buf1, slog.AllLevels), slog.ChannelFilter("db"))
+	h2 := handler.Filtered(handler.NewIOWriter(buf2, slog.NormalLevels)).
+		AddFilter(slog.Not(slog.ChannelFilter("db")))
+	assert.True(t, h1.IsHandling(slog.DebugLevel))
+	assert.False(t, h2.IsHandling(slog.PanicLevel))
+
+	l := slog.NewWithHandlers(h1, h2)
+	reg := slog.NewRegistry(l)
+	l.Info("app message")
+	reg.Get("db").Info("db message")
+	assert.NoErr(t, l.Close())
+
+	assert.StrContains(t, buf1.String(), "db message")
+	assert.NotContains(t, buf1.String(), "app message")
+	assert.StrContains(t, buf2.String(), "app message")
+	assert.NotContains(t, buf2.String(), "db message")
+}
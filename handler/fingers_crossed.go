@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+
+	"github.com/gookit/slog"
+)
+
+// there are default limits of the FingersCrossedHandler
+const (
+	DefaultFingersCrossedBufferSize = 100
+	DefaultFingersCrossedMaxGroups  = 1000
+)
+
+// FingersCrossedHandler wrap a handler, buffer the records in memory and discard them,
+// until a record at or above the activation level arrives. Then flush the buffered
+// records and the activation record to the wrapped handler.
+//
+// The buffers can be grouped by a key, eg: the request ID. so one failing request only
+// dumps its own records. see GroupKey, GroupByCtxKey(), GroupByField()
+//
+// Usage:
+//
+//	h := handler.FingersCrossed(fileHandler, slog.ErrorLevel, func(h *handler.FingersCrossedHandler) {
+//		h.GroupKey = handler.GroupByCtxKey(httplog.RequestIDKey)
+//	})
+type FingersCrossedHandler struct {
+	// Handler the wrapped handler
+	Handler slog.Handler
+	// ActivationLevel the records at or above the level will activate the handler.
+	ActivationLevel slog.Level
+	// BufferSize max number of the buffered records per group, the oldest will be dropped on full.
+	// default is DefaultFingersCrossedBufferSize
+	BufferSize int
+	// MaxGroups max number of the buffer groups, the oldest group will be discarded on full.
+	// default is DefaultFingersCrossedMaxGroups
+	MaxGroups int
+	// StayActivated pass the records directly to the Handler after activated.
+	// default is false, will reset to buffering after flush the buffered records.
+	StayActivated bool
+	// GroupKey get the buffer group key of the record. default all records are in one group.
+	GroupKey func(r *slog.Record) string
+
+	mu sync.Mutex
+	// groups by key, the value is *fcGroup. the list is ordered by created time.
+	groups map[string]*list.Element
+	order  *list.List
+}
+
+// buffer group of the FingersCrossedHandler
+type fcGroup struct {
+	key string
+	// ring buffer of the records, the head is the index of the oldest on full.
+	records   []*slog.Record
+	head      int
+	activated bool
+}
+
+// FingersCrossed create a new FingersCrossedHandler
+func FingersCrossed(h slog.Handler, activationLevel slog.Level, fns ...func(h *FingersCrossedHandler)) *FingersCrossedHandler {
+	fh := &FingersCrossedHandler{
+		Handler:         h,
+		ActivationLevel: activationLevel,
+		BufferSize:      DefaultFingersCrossedBufferSize,
+		MaxGroups:       DefaultFingersCrossedMaxGroups,
+		// init
+		groups: make(map[string]*list.Element),
+		order:  list.New(),
+	}
+
+	for _, fn := range fns {
+		fn(fh)
+	}
+	return fh
+}
+
+// GroupByCtxKey group the buffers by the value of the context key. eg: httplog.RequestIDKey
+func GroupByCtxKey(key slog.CtxKey) func(r *slog.Record) string {
+	return func(r *slog.Record) string {
+		if r.Ctx == nil {
+			return ""
+		}
+		if val, ok := key.AnyValue(r.Ctx); ok {
+			return fmt.Sprint(val)
+		}
+		return ""
+	}
+}
+
+// GroupByField group the buffers by the field value. see slog.Record.Lookup() for the search order.
+func GroupByField(key string) func(r *slog.Record) string {
+	return func(r *slog.Record) string {
+		if val, ok := r.Lookup(key); ok {
+			return fmt.Sprint(val)
+		}
+		return ""
+	}
+}
+
+// IsHandling check level is enabled by the wrapped handler
+func (h *FingersCrossedHandler) IsHandling(level slog.Level) bool {
+	return h.Handler.IsHandling(level)
+}
+
+// Handle log record. buffer the record, or flush the buffered records on activated.
+//
+// The buffered records are written to the wrapped handler without the lock held,
+// so a slow write does not block other groups.
+func (h *FingersCrossedHandler) Handle(r *slog.Record) error {
+	var key string
+	if h.GroupKey != nil {
+		key = h.GroupKey(r)
+	}
+
+	h.mu.Lock()
+	g := h.group(key)
+	if g.activated {
+		h.mu.Unlock()
+		return h.Handler.Handle(r)
+	}
+
+	if !h.ActivationLevel.ShouldHandling(r.Level) {
+		// the record will be reused by the logger, so buffer a copy of it.
+		g.push(r.Clone(), h.bufferSize())
+		h.mu.Unlock()
+		return nil
+	}
+
+	// activated: take the buffered records
+	buffered := g.take()
+	if h.StayActivated {
+		g.activated = true
+	} else {
+		h.removeGroup(key)
+	}
+	h.mu.Unlock()
+
+	var err error
+	for _, br := range buffered {
+		if err1 := h.Handler.Handle(br); err1 != nil && err == nil {
+			err = err1
+		}
+	}
+
+	if err1 := h.Handler.Handle(r); err1 != nil && err == nil {
+		err = err1
+	}
+	return err
+}
+
+// push the record to the ring buffer, the oldest will be overwritten on full.
+func (g *fcGroup) push(r *slog.Record, size int) {
+	if len(g.records) < size {
+		g.records = append(g.records, r)
+		return
+	}
+
+	// full: overwrite the oldest. the dropped record is not referenced anymore.
+	g.records[g.head] = r
+	g.head = (g.head + 1) % len(g.records)
+}
+
+// take the buffered records in order, and reset the buffer.
+func (g *fcGroup) take() []*slog.Record {
+	rs := append(g.records[g.head:len(g.records):len(g.records)], g.records[:g.head]...)
+	g.records, g.head = nil, 0
+	return rs
+}
+
+// get or create the group by key. h.mu is held.
+func (h *FingersCrossedHandler) group(key string) *fcGroup {
+	// init on the handler is created without FingersCrossed()
+	if h.groups == nil {
+		h.groups = make(map[string]*list.Element)
+		h.order = list.New()
+	}
+
+	if el, ok := h.groups[key]; ok {
+		return el.Value.(*fcGroup)
+	}
+
+	maxGroups := h.MaxGroups
+	if maxGroups <= 0 {
+		maxGroups = DefaultFingersCrossedMaxGroups
+	}
+
+	// discard the oldest group on full
+	for len(h.groups) >= maxGroups {
+		oldest := h.order.Front()
+		h.removeGroup(oldest.Value.(*fcGroup).key)
+	}
+
+	g := &fcGroup{key: key}
+	h.groups[key] = h.order.PushBack(g)
+	return g
+}
+
+// h.mu is held.
+func (h *FingersCrossedHandler) removeGroup(key string) {
+	if el, ok := h.groups[key]; ok {
+		h.order.Remove(el)
+		delete(h.groups, key)
+	}
+}
+
+func (h *FingersCrossedHandler) bufferSize() int {
+	if h.BufferSize > 0 {
+		return h.BufferSize
+	}
+	return DefaultFingersCrossedBufferSize
+}
+
+// Buffered get the number of the buffered records of all groups
+func (h *FingersCrossedHandler) Buffered() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var n int
+	for _, el := range h.groups {
+		n += len(el.Value.(*fcGroup).records)
+	}
+	return n
+}
+
+// Reset discard all buffered records and the activated status
+func (h *FingersCrossedHandler) Reset() {
+	h.mu.Lock()
+	h.groups, h.order = nil, nil
+	h.mu.Unlock()
+}
+
+// Flush the wrapped handler. the buffered records will not be flushed.
+func (h *FingersCrossedHandler) Flush() error { return h.Handler.Flush() }
+
+// Close discard the buffered records and close the wrapped handler
+func (h *FingersCrossedHandler) Close() error {
+	h.Reset()
+	return h.Handler.Close()
+}
@@ -0,0 +1,184 @@
+package handler_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/slogtest"
+)
+
+func TestFingersCrossed(t *testing.T) {
+	rh := slogtest.NewRecordingHandler()
+	h := handler.FingersCrossed(rh, slog.ErrorLevel, func(h *handler.FingersCrossedHandler) {
+		h.BufferSize = 3
+	})
+	assert.True(t, h.IsHandling(slog.DebugLevel))
+
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+	l.Debug("debug message")
+	l.Info("info message1")
+	l.Info("info message2")
+	l.Warn("warn message")
+	assert.Eq(t, 3, h.Buffered())
+	assert.Eq(t, 0, rh.Len())
+
+	// activated: flush the buffer, the oldest record has been dropped
+	l.Error("error message")
+	assert.Eq(t, []string{"info message1", "info message2", "warn message", "error message"}, rh.Messages())
+	assert.Eq(t, 0, h.Buffered())
+
+	// reset to buffering
+	l.Info("info message3")
+	assert.Eq(t, 1, h.Buffered())
+	assert.Eq(t, 4, rh.Len())
+
+	// the buffered records are copied
+	l.WithData(slog.M{"key": "val"}).Panic("panic message")
+	assert.Eq(t, []string{"info message3", "panic message"}, rh.Messages()[4:])
+	assert.Eq(t, slog.M{"key": "val"}, rh.Last().Data)
+
+	// close: discard the buffered records
+	l.Info("info message4")
+	assert.NoErr(t, l.Close())
+	assert.Eq(t, 0, h.Buffered())
+	assert.Eq(t, 6, rh.Len())
+	assert.Eq(t, 1, rh.Closed())
+}
+
+func TestFingersCrossed_StayActivated(t *testing.T) {
+	rh := slogtest.NewRecordingHandler()
+	h := handler.FingersCrossed(rh, slog.WarnLevel, func(h *handler.FingersCrossedHandler) {
+		h.StayActivated = true
+	})
+
+	l := slog.NewWithHandlers(h)
+	l.Debug("debug message")
+	l.Warn("warn message")
+	l.Info("info message")
+	assert.Eq(t, []string{"debug message", "warn message", "info message"}, rh.Messages())
+	assert.Eq(t, 0, h.Buffered())
+
+	// reset the activated status
+	h.Reset()
+	l.Info("info message2")
+	assert.Eq(t, 1, h.Buffered())
+	assert.Eq(t, 3, rh.Len())
+}
+
+func TestFingersCrossed_group(t *testing.T) {
+	reqKey := slog.NewContextKey[string]("request_id")
+	ctx1 := reqKey.WithValue(context.Background(), "req-1")
+	ctx2 := reqKey.WithValue(context.Background(), "req-2")
+
+	rh := slogtest.NewRecordingHandler()
+	h := handler.FingersCrossed(rh, slog.ErrorLevel, func(h *handler.FingersCrossedHandler) {
+		h.GroupKey = handler.GroupByCtxKey(reqKey)
+		h.MaxGroups = 3
+	})
+
+	l := slog.NewWithHandlers(h)
+	l.WithCtx(ctx1).Info("req-1 message1")
+	l.WithCtx(ctx2).Info("req-2 message1")
+	l.WithCtx(ctx1).Info("req-1 message2")
+	l.Info("no request message")
+	assert.Eq(t, 4, h.Buffered())
+
+	// one failing request only dumps its own records
+	l.WithCtx(ctx1).Error("req-1 error")
+	assert.Eq(t, []string{"req-1 message1", "req-1 message2", "req-1 error"}, rh.Messages())
+
+	// the oldest group is discarded on the groups is full
+	l.WithCtx(reqKey.WithValue(context.Background(), "req-3")).Info("req-3 message1")
+	l.WithCtx(reqKey.WithValue(context.Background(), "req-4")).Info("req-4 message1")
+	l.WithCtx(ctx2).Error("req-2 error")
+	assert.Eq(t, "req-2 error", rh.Last().Message)
+	assert.Eq(t, 4, rh.Len())
+	// req-3, req-4. the "no request" group is discarded on create the new req-2 group
+	assert.Eq(t, 2, h.Buffered())
+
+	// group by field
+	rh.Reset()
+	h.Reset()
+	h.GroupKey = handler.GroupByField("order_id")
+	l.WithField("order_id", "o-1").Info("o-1 message")
+	l.WithData(slog.M{"order_id": "o-2"}).Info("o-2 message")
+	l.Build(slog.InfoLevel).Int("order_id", 3).Msg("o-3 message")
+	l.Build(slog.ErrorLevel).Int("order_id", 3).Msg("o-3 error")
+	l.WithData(slog.M{"order_id": "o-2"}).Error("o-2 error")
+	assert.Eq(t, []string{"o-3 message", "o-3 error", "o-2 message", "o-2 error"}, rh.Messages())
+}
+
+// blockingHandler block the Handle of the "block" message until the release is closed
+type blockingHandler struct {
+	*slogtest.RecordingHandler
+	entered, release chan struct{}
+}
+
+func (h *blockingHandler) Handle(r *slog.Record) error {
+	if r.Message == "block" {
+		close(h.entered)
+		<-h.release
+	}
+	return h.RecordingHandler.Handle(r)
+}
+
+func TestFingersCrossed_writeWithoutLock(t *testing.T) {
+	bh := &blockingHandler{
+		RecordingHandler: slogtest.NewRecordingHandler(),
+		entered:          make(chan struct{}),
+		release:          make(chan struct{}),
+	}
+	h := handler.FingersCrossed(bh, slog.ErrorLevel, func(h *handler.FingersCrossedHandler) {
+		h.BufferSize = 2
+		h.GroupKey = handler.GroupByField("group")
+	})
+
+	newRecord := func(level slog.Level, group, msg string) *slog.Record {
+		r := slog.New().Record()
+		r.Level, r.Message = level, msg
+		return r.AddField("group", group)
+	}
+
+	// the ring buffer keeps the latest records in order
+	for i := 0; i < 7; i++ {
+		assert.NoErr(t, h.Handle(newRecord(slog.InfoLevel, "a", fmt.Sprint("message", i))))
+	}
+	assert.Eq(t, 2, h.Buffered())
+	assert.NoErr(t, h.Handle(newRecord(slog.InfoLevel, "a", "block")))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoErr(t, h.Handle(newRecord(slog.ErrorLevel, "a", "error message")))
+	}()
+	<-bh.entered
+
+	// the other groups are not blocked by the slow write
+	assert.NoErr(t, h.Handle(newRecord(slog.InfoLevel, "b", "other message")))
+	assert.Eq(t, 1, h.Buffered())
+
+	close(bh.release)
+	<-done
+	assert.Eq(t, []string{"message6", "block", "error message"}, bh.Messages())
+}
+
+func TestFingersCrossed_literal(t *testing.T) {
+	rh := slogtest.NewRecordingHandler()
+	// create without the constructor
+	h := &handler.FingersCrossedHandler{Handler: rh, ActivationLevel: slog.ErrorLevel}
+
+	l := slog.NewWithHandlers(h)
+	l.Info("info message")
+	assert.Eq(t, 1, h.Buffered())
+	l.Error("error message")
+	assert.Eq(t, []string{"info message", "error message"}, rh.Messages())
+
+	h.Reset()
+	l.Info("info message")
+	assert.Eq(t, 1, h.Buffered())
+}
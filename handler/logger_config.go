@@ -0,0 +1,611 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gookit/slog"
+	"gopkg.in/yaml.v3"
+)
+
+// LoggerConfig the config for create a whole Logger. can be loaded from JSON, YAML files.
+//
+// Example(JSON):
+//
+//	{
+//		"channel": "app",
+//		"level": "debug",
+//		"caller_flag": "fn_line",
+//		"global_fields": {"app": "demo"},
+//		"processors": ["hostname"],
+//		"handlers": [
+//			{"name": "console", "type": "console", "level": "info"},
+//			{
+//				"name": "file",
+//				"type": "rotate_file",
+//				"levels": ["error", "warn"],
+//				"formatter": {"type": "json", "aliases": {"message": "msg"}},
+//				"options": {"logfile": "logs/error.log", "max_size": 10485760}
+//			}
+//		]
+//	}
+type LoggerConfig struct {
+	// Name of the logger. default is "logger"
+	Name string `json:"name" yaml:"name"`
+	// Channel name of the logger. default is slog.DefaultChannelName
+	Channel string `json:"channel" yaml:"channel"`
+	// Level max level of the logger. eg: "info". default is not limit
+	Level string `json:"level" yaml:"level"`
+	// ReportCaller on writing log record. default is true
+	ReportCaller *bool `json:"report_caller" yaml:"report_caller"`
+	// CallerFlag name. allow: fnl_fcn, full, func, fc_line, pkg, pkg_fnl, fp_line, fn_line, fc_name
+	CallerFlag string `json:"caller_flag" yaml:"caller_flag"`
+	// LowerLevelName use lower level name
+	LowerLevelName bool `json:"lower_level_name" yaml:"lower_level_name"`
+	// GlobalFields will be added to all log records
+	GlobalFields map[string]any `json:"global_fields" yaml:"global_fields"`
+	// Processors names of the registered processor types. see RegisterProcessorType()
+	Processors []string `json:"processors" yaml:"processors"`
+	// Handlers config list
+	Handlers []*HandlerConfig `json:"handlers" yaml:"handlers"`
+}
+
+// HandlerConfig the config for create a handler by the registered type. see RegisterHandlerType()
+type HandlerConfig struct {
+	// Name of the handler, must be unique in the LoggerConfig. default is the Type
+	Name string `json:"name" yaml:"name"`
+	// Type name of the registered handler type. eg: "console", "file", "rotate_file"
+	Type string `json:"type" yaml:"type"`
+	// Level max level for the handler. eg: "info"
+	Level string `json:"level" yaml:"level"`
+	// Levels list for the handler, it takes precedence over the Level. eg: ["error", "warn"]
+	Levels []string `json:"levels" yaml:"levels"`
+	// Formatter config, if is nil will use the default formatter of the handler.
+	Formatter *FormatterConfig `json:"formatter" yaml:"formatter"`
+	// Processors names of the registered processor types, only run for the handler.
+	// the handler must implement the slog.ProcessableHandler. see slog.Processable
+	Processors []string `json:"processors" yaml:"processors"`
+	// Options for the handler type. eg: {"logfile": "logs/app.log"}
+	Options map[string]any `json:"options" yaml:"options"`
+}
+
+// FormatterConfig the config for create a formatter
+type FormatterConfig struct {
+	// Type of the formatter. allow: text, json. default is text
+	Type string `json:"type" yaml:"type"`
+	// Template for the text formatter. default is slog.DefaultTemplate
+	Template string `json:"template" yaml:"template"`
+	// EnableColor for the text formatter
+	EnableColor bool `json:"enable_color" yaml:"enable_color"`
+	// Fields for the json formatter. default is slog.DefaultFields
+	Fields []string `json:"fields" yaml:"fields"`
+	// Aliases for the json formatter. eg: {"message": "msg"}
+	Aliases map[string]string `json:"aliases" yaml:"aliases"`
+	// PrettyPrint for the json formatter
+	PrettyPrint bool `json:"pretty_print" yaml:"pretty_print"`
+	// TimeFormat the time format layout
+	TimeFormat string `json:"time_format" yaml:"time_format"`
+}
+
+// ConfigError the error of the LoggerConfig, with the path of the offending value. eg: "handlers[1].level"
+type ConfigError struct {
+	Path string
+	Err  error
+}
+
+// Error message
+func (e *ConfigError) Error() string {
+	return "slog: invalid config " + e.Path + ": " + e.Err.Error()
+}
+
+// Unwrap the error
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// prefix the path for the error. eg: "level" -> "handlers[1].level"
+func wrapConfigErr(path string, err error) error {
+	var ce *ConfigError
+	if errors.As(err, &ce) {
+		return &ConfigError{Path: path + "." + ce.Path, Err: ce.Err}
+	}
+	return &ConfigError{Path: path, Err: err}
+}
+
+func configErrf(path, format string, args ...any) error {
+	return &ConfigError{Path: path, Err: fmt.Errorf(format, args...)}
+}
+
+//
+// ---------------------------------------------------------------------------
+// registry for handler types, processor types and decoders
+// ---------------------------------------------------------------------------
+//
+
+// HandlerFactory create a handler by config. the lf has been set levels by the config.
+//
+// TIP: the formatter will be set after created on HandlerConfig.Formatter is not nil.
+type HandlerFactory func(hc *HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error)
+
+// ProcessorFactory create a processor
+type ProcessorFactory func() slog.Processor
+
+// ConfigDecoder decode the config contents to the v. eg: json.Unmarshal, yaml.Unmarshal
+type ConfigDecoder func(bs []byte, v any) error
+
+var (
+	regMu          sync.RWMutex
+	handlerTypes   = map[string]HandlerFactory{}
+	processorTypes = map[string]ProcessorFactory{}
+	// decoders by the file ext, without the dot. eg: "json", "yaml"
+	configDecoders = map[string]ConfigDecoder{
+		"json": json.Unmarshal,
+		"yaml": yaml.Unmarshal,
+		"yml":  yaml.Unmarshal,
+	}
+)
+
+// RegisterHandlerType register a handler type for create by HandlerConfig.
+//
+// Built-in types: console, stdout, stderr, file, rotate_file
+func RegisterHandlerType(typ string, fn HandlerFactory) {
+	regMu.Lock()
+	handlerTypes[typ] = fn
+	regMu.Unlock()
+}
+
+// HandlerTypes get the names of the registered handler types
+func HandlerTypes() []string {
+	regMu.RLock()
+	defer regMu.RUnlock()
+
+	names := make([]string, 0, len(handlerTypes))
+	for name := range handlerTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterProcessorType register a processor type for LoggerConfig.Processors
+//
+// Built-in types: hostname, memory_usage, unique_id, trace
+func RegisterProcessorType(name string, fn ProcessorFactory) {
+	regMu.Lock()
+	processorTypes[name] = fn
+	regMu.Unlock()
+}
+
+// get the registered processor type
+func processorType(name string) (ProcessorFactory, bool) {
+	regMu.RLock()
+	defer regMu.RUnlock()
+	fn, ok := processorTypes[name]
+	return fn, ok
+}
+
+// RegisterConfigDecoder register a decoder for load config file by the ext.
+// the "json", "yaml", "yml" are registered by default. eg:
+//
+//	handler.RegisterConfigDecoder("toml", toml.Unmarshal)
+//
+// NOTE: the decoder must decode the map as map[string]any
+func RegisterConfigDecoder(ext string, fn ConfigDecoder) {
+	regMu.Lock()
+	configDecoders[strings.TrimPrefix(ext, ".")] = fn
+	regMu.Unlock()
+}
+
+func init() {
+	// the console, stdout, stderr have no options, will reject any option.
+	RegisterHandlerType("console", func(hc *HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error) {
+		if err := hc.DecodeOptions(&struct{}{}); err != nil {
+			return nil, err
+		}
+		return NewConsoleWithLF(lf), nil
+	})
+	RegisterHandlerType("stdout", func(hc *HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error) {
+		if err := hc.DecodeOptions(&struct{}{}); err != nil {
+			return nil, err
+		}
+		return NewIOWriterWithLF(os.Stdout, lf), nil
+	})
+	RegisterHandlerType("stderr", func(hc *HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error) {
+		if err := hc.DecodeOptions(&struct{}{}); err != nil {
+			return nil, err
+		}
+		return NewIOWriterWithLF(os.Stderr, lf), nil
+	})
+	RegisterHandlerType("file", func(hc *HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error) {
+		return newFileByConfig(hc, lf, NewEmptyConfig())
+	})
+	RegisterHandlerType("rotate_file", func(hc *HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error) {
+		return newFileByConfig(hc, lf, NewConfig())
+	})
+
+	RegisterProcessorType("hostname", slog.AddHostname)
+	RegisterProcessorType("memory_usage", func() slog.Processor { return slog.MemoryUsage })
+	RegisterProcessorType("unique_id", func() slog.Processor { return slog.AddUniqueID("unique_id") })
+	RegisterProcessorType("trace", func() slog.Processor { return slog.TraceProcessor() })
+}
+
+// create file handler by the options. the options is same as the Config
+func newFileByConfig(hc *HandlerConfig, lf slog.LevelFormattable, c *Config) (slog.Handler, error) {
+	if err := hc.DecodeOptions(c); err != nil {
+		return nil, err
+	}
+	if c.Logfile == "" {
+		return nil, configErrf("options.logfile", "cannot be empty")
+	}
+
+	w, err := c.CreateWriter()
+	if err != nil {
+		return nil, configErrf("options", "create writer: %w", err)
+	}
+	return NewSyncCloserWithLF(w, lf), nil
+}
+
+//
+// ---------------------------------------------------------------------------
+// load and create logger
+// ---------------------------------------------------------------------------
+//
+
+// NewLoggerConfig create an empty LoggerConfig
+func NewLoggerConfig() *LoggerConfig { return &LoggerConfig{} }
+
+// LoggerConfigFromJSON parse the LoggerConfig from JSON contents
+func LoggerConfigFromJSON(bs []byte) (*LoggerConfig, error) {
+	return ParseLoggerConfig(bs, "json")
+}
+
+// ParseLoggerConfig parse the LoggerConfig by the decoder of the format. eg: "json", "yaml"
+func ParseLoggerConfig(bs []byte, format string) (*LoggerConfig, error) {
+	regMu.RLock()
+	decode, ok := configDecoders[format]
+	regMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("slog: no config decoder for the format %q", format)
+	}
+
+	c := NewLoggerConfig()
+	if err := decode(bs, c); err != nil {
+		return nil, fmt.Errorf("slog: decode %s config: %w", format, err)
+	}
+	return c, nil
+}
+
+// LoadLoggerConfig load the LoggerConfig from file, the decoder is selected by the file ext.
+func LoadLoggerConfig(file string) (*LoggerConfig, error) {
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	return ParseLoggerConfig(bs, strings.TrimPrefix(filepath.Ext(file), "."))
+}
+
+// NewLoggerFromFile create a Logger by the config file. see LoadLoggerConfig()
+func NewLoggerFromFile(file string) (*slog.Logger, error) {
+	c, err := LoadLoggerConfig(file)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewLogger()
+}
+
+// caller flag names for LoggerConfig.CallerFlag
+var callerFlags = map[string]slog.CallerFlagMode{
+	"fnl_fcn": slog.CallerFlagFnlFcn,
+	"full":    slog.CallerFlagFull,
+	"func":    slog.CallerFlagFunc,
+	"fc_line": slog.CallerFlagFcLine,
+	"pkg":     slog.CallerFlagPkg,
+	"pkg_fnl": slog.CallerFlagPkgFnl,
+	"fp_line": slog.CallerFlagFpLine,
+	"fn_line": slog.CallerFlagFnLine,
+	"fc_name": slog.CallerFlagFcName,
+}
+
+// Validate the config, the error is *ConfigError with the offending path.
+func (c *LoggerConfig) Validate() error {
+	if c.Level != "" {
+		if _, err := parseLevel(c.Level); err != nil {
+			return &ConfigError{Path: "level", Err: err}
+		}
+	}
+	if _, ok := callerFlags[c.CallerFlag]; c.CallerFlag != "" && !ok {
+		return configErrf("caller_flag", "unknown caller flag %q", c.CallerFlag)
+	}
+
+	for i, name := range c.Processors {
+		if _, ok := processorType(name); !ok {
+			return configErrf(fmt.Sprintf("processors[%d]", i), "unknown processor type %q", name)
+		}
+	}
+
+	names := make(map[string]bool, len(c.Handlers))
+	for i, hc := range c.Handlers {
+		path := fmt.Sprintf("handlers[%d]", i)
+		if hc == nil {
+			return configErrf(path, "cannot be empty")
+		}
+		if err := hc.validate(); err != nil {
+			return wrapConfigErr(path, err)
+		}
+
+		name := hc.HandlerName()
+		if names[name] {
+			return configErrf(path+".name", "duplicate handler name %q", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// NewLogger create a new Logger by the config.
+//
+// NOTE: the created handlers will be closed on the error occurred.
+func (c *LoggerConfig) NewLogger() (*slog.Logger, error) {
+	hs, err := c.CreateHandlers()
+	if err != nil {
+		return nil, err
+	}
+
+	name := c.Name
+	if name == "" {
+		name = "logger"
+	}
+
+	l := slog.NewWithName(name)
+	c.ApplyTo(l)
+	l.AddHandlers(hs...)
+	return l, nil
+}
+
+// ApplyTo apply the logger options and processors to the logger. handlers are not included.
+//
+// NOTE: should call Validate() before apply.
+func (c *LoggerConfig) ApplyTo(l *slog.Logger) {
+	if c.Channel != "" {
+		l.ChannelName = c.Channel
+	}
+	if c.Level != "" {
+		lv, _ := parseLevel(c.Level)
+		l.SetMaxLevel(lv)
+	}
+	if c.ReportCaller != nil {
+		l.ReportCaller = *c.ReportCaller
+	}
+	if flag, ok := callerFlags[c.CallerFlag]; ok {
+		l.CallerFlag = flag
+	}
+
+	l.LowerLevelName = c.LowerLevelName
+	// replace the global fields, the old fields will be removed.
+	l.GlobalFields = nil
+	if len(c.GlobalFields) > 0 {
+		l.GlobalFields = make(map[string]any, len(c.GlobalFields))
+		for k, v := range c.GlobalFields {
+			l.GlobalFields[k] = v
+		}
+	}
+
+	for _, name := range c.Processors {
+		if fn, ok := processorType(name); ok {
+			l.AddProcessor(fn())
+		}
+	}
+}
+
+// CreateHandlers create the handlers by config. will validate the config before create.
+//
+// NOTE: the created handlers will be closed on the error occurred.
+func (c *LoggerConfig) CreateHandlers() ([]slog.Handler, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	hs := make([]slog.Handler, 0, len(c.Handlers))
+	for i, hc := range c.Handlers {
+		h, err := hc.CreateHandler()
+		if err != nil {
+			for _, created := range hs {
+				_ = created.Close()
+			}
+			return nil, wrapConfigErr(fmt.Sprintf("handlers[%d]", i), err)
+		}
+		hs = append(hs, h)
+	}
+	return hs, nil
+}
+
+//
+// ---------------------------------------------------------------------------
+// handler config
+// ---------------------------------------------------------------------------
+//
+
+// HandlerName get the name of the handler, default is the Type
+func (hc *HandlerConfig) HandlerName() string {
+	if hc.Name != "" {
+		return hc.Name
+	}
+	return hc.Type
+}
+
+func (hc *HandlerConfig) validate() error {
+	if hc.Type == "" {
+		return configErrf("type", "cannot be empty")
+	}
+
+	regMu.RLock()
+	_, ok := handlerTypes[hc.Type]
+	regMu.RUnlock()
+	if !ok {
+		return configErrf("type", "unknown handler type %q", hc.Type)
+	}
+
+	if _, err := hc.levelFormattable(); err != nil {
+		return err
+	}
+	if hc.Formatter != nil {
+		if _, err := hc.Formatter.CreateFormatter(); err != nil {
+			return wrapConfigErr("formatter", err)
+		}
+	}
+
+	for i, name := range hc.Processors {
+		if _, ok := processorType(name); !ok {
+			return configErrf(fmt.Sprintf("processors[%d]", i), "unknown processor type %q", name)
+		}
+	}
+	return nil
+}
+
+// CreateHandler create a handler by the config.
+func (hc *HandlerConfig) CreateHandler() (slog.Handler, error) {
+	regMu.RLock()
+	fn, ok := handlerTypes[hc.Type]
+	regMu.RUnlock()
+	if !ok {
+		return nil, configErrf("type", "unknown handler type %q", hc.Type)
+	}
+
+	lf, err := hc.levelFormattable()
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := fn(hc, lf)
+	if err != nil {
+		return nil, err
+	}
+
+	if hc.Formatter != nil {
+		f, err := hc.Formatter.CreateFormatter()
+		if err != nil {
+			_ = h.Close()
+			return nil, wrapConfigErr("formatter", err)
+		}
+		if fh, ok := h.(slog.Formattable); ok {
+			fh.SetFormatter(f)
+		}
+	}
+
+	if len(hc.Processors) > 0 {
+		ph, ok := h.(slog.ProcessableHandler)
+		if !ok {
+			_ = h.Close()
+			return nil, configErrf("processors", "the handler type %q does not support processors", hc.Type)
+		}
+
+		for i, name := range hc.Processors {
+			fn, ok := processorType(name)
+			if !ok {
+				_ = h.Close()
+				return nil, configErrf(fmt.Sprintf("processors[%d]", i), "unknown processor type %q", name)
+			}
+			ph.AddProcessor(fn())
+		}
+	}
+	return h, nil
+}
+
+// DecodeOptions decode the Options to the v by JSON tags. eg: decode to the *Config
+//
+// The unknown option keys are not allowed, will return *ConfigError with the path. eg: "options.max_sise"
+func (hc *HandlerConfig) DecodeOptions(v any) error {
+	if len(hc.Options) == 0 {
+		return nil
+	}
+
+	bs, err := json.Marshal(hc.Options)
+	if err == nil {
+		dec := json.NewDecoder(bytes.NewReader(bs))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(v)
+	}
+
+	if err != nil {
+		// eg: json: unknown field "max_sise"
+		if msg := err.Error(); strings.HasPrefix(msg, unknownFieldPrefix) {
+			key := strings.TrimSuffix(strings.TrimPrefix(msg, unknownFieldPrefix), `"`)
+			return configErrf("options."+key, "unknown option")
+		}
+		return &ConfigError{Path: "options", Err: err}
+	}
+	return nil
+}
+
+const unknownFieldPrefix = `json: unknown field "`
+
+func (hc *HandlerConfig) levelFormattable() (slog.LevelFormattable, error) {
+	if len(hc.Levels) > 0 {
+		levels := make([]slog.Level, len(hc.Levels))
+		for i, name := range hc.Levels {
+			lv, err := parseLevel(name)
+			if err != nil {
+				return nil, &ConfigError{Path: fmt.Sprintf("levels[%d]", i), Err: err}
+			}
+			levels[i] = lv
+		}
+		return slog.NewLvsFormatter(levels), nil
+	}
+
+	if hc.Level == "" {
+		return slog.NewLvFormatter(slog.TraceLevel), nil
+	}
+
+	lv, err := parseLevel(hc.Level)
+	if err != nil {
+		return nil, &ConfigError{Path: "level", Err: err}
+	}
+	return slog.NewLvFormatter(lv), nil
+}
+
+// CreateFormatter create a formatter by the config
+func (fc *FormatterConfig) CreateFormatter() (slog.Formatter, error) {
+	switch fc.Type {
+	case "", "text":
+		f := slog.NewTextFormatter()
+		if fc.Template != "" {
+			f.SetTemplate(fc.Template)
+		}
+		f.EnableColor = fc.EnableColor
+		if fc.TimeFormat != "" {
+			f.TimeFormat = fc.TimeFormat
+		}
+		return f, nil
+	case "json":
+		f := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+			f.Aliases = fc.Aliases
+			f.PrettyPrint = fc.PrettyPrint
+			if len(fc.Fields) > 0 {
+				f.Fields = fc.Fields
+			}
+			if fc.TimeFormat != "" {
+				f.TimeFormat = fc.TimeFormat
+			}
+		})
+		return f, nil
+	}
+	return nil, configErrf("type", "unknown formatter type %q", fc.Type)
+}
+
+// parse the level name, the empty and unknown names are not allowed.
+func parseLevel(name string) (slog.Level, error) {
+	if name == "" {
+		return 0, errors.New("level name cannot be empty")
+	}
+
+	lv, err := slog.StringToLevel(name)
+	if err != nil || lv.Name() == "UNKNOWN" {
+		return 0, fmt.Errorf("unknown level name %q", name)
+	}
+	return lv, nil
+}
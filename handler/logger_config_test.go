@@ -0,0 +1,216 @@
+package handler_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestLoggerConfig_NewLogger(t *testing.T) {
+	buf := new(bytes.Buffer)
+	handler.RegisterHandlerType("test_buffer", func(hc *handler.HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error) {
+		var opts struct {
+			Prefix string `json:"prefix"`
+		}
+		if err := hc.DecodeOptions(&opts); err != nil {
+			return nil, err
+		}
+
+		buf.WriteString(opts.Prefix)
+		return handler.NewIOWriterWithLF(buf, lf), nil
+	})
+	assert.Contains(t, handler.HandlerTypes(), "test_buffer")
+
+	c, err := handler.LoggerConfigFromJSON([]byte(`{
+	"name": "app",
+	"channel": "web",
+	"level": "info",
+	"caller_flag": "fn_line",
+	"global_fields": {"app": "demo"},
+	"processors": ["unique_id"],
+	"handlers": [
+		{
+			"type": "test_buffer",
+			"levels": ["error", "warn"],
+			"formatter": {"type": "json", "fields": ["channel", "level", "message"], "aliases": {"message": "msg"}},
+			"processors": ["hostname"],
+			"options": {"prefix": "start:"}
+		},
+		{
+			"name": "file",
+			"type": "file",
+			"level": "notice",
+			"formatter": {"template": "{{level}} {{message}}\n"},
+			"options": {"logfile": "./testdata/logger-config.log"}
+		}
+	]
+}`))
+	assert.NoErr(t, err)
+
+	l, err := c.NewLogger()
+	assert.NoErr(t, err)
+	assert.Eq(t, "app", l.Name())
+	assert.Eq(t, "web", l.ChannelName)
+	assert.Eq(t, slog.CallerFlagFnLine, l.CallerFlag)
+	assert.Eq(t, 2, l.HandlersNum())
+
+	l.Debug("debug message")
+	l.Info("info message")
+	l.Notice("notice message")
+	l.Warn("warn message")
+	l.Error("error message")
+	assert.NoErr(t, l.Close())
+
+	s := buf.String()
+	assert.True(t, strings.HasPrefix(s, "start:"))
+	assert.StrContains(t, s, `"msg":"warn message"`)
+	assert.StrContains(t, s, `"msg":"error message"`)
+	assert.StrContains(t, s, `"app":"demo"`)
+	assert.StrContains(t, s, `"hostname":`)
+	assert.StrContains(t, s, `"unique_id":`)
+	assert.NotContains(t, s, "info message")
+
+	bs, err := os.ReadFile("./testdata/logger-config.log")
+	assert.NoErr(t, err)
+	s = string(bs)
+	assert.True(t, strings.HasPrefix(s, "NOTICE notice message\n"))
+	assert.StrContains(t, s, "WARNING warn message\n")
+	assert.StrContains(t, s, "ERROR error message\n")
+	assert.NotContains(t, s, "info message")
+	// the handler processors only run for the handler
+	assert.NotContains(t, s, "hostname")
+}
+
+func TestLoggerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		json string
+		path string
+	}{
+		{`{"level": "invalid"}`, "level"},
+		{`{"caller_flag": "invalid"}`, "caller_flag"},
+		{`{"processors": ["hostname", "invalid"]}`, "processors[1]"},
+		{`{"handlers": [{"type": "console"}, {"type": ""}]}`, "handlers[1].type"},
+		{`{"handlers": [{"type": "not-exists"}]}`, "handlers[0].type"},
+		{`{"handlers": [{"type": "console", "levels": ["info", "invalid"]}]}`, "handlers[0].levels[1]"},
+		{`{"handlers": [{"type": "console", "level": "invalid"}]}`, "handlers[0].level"},
+		{`{"handlers": [{"type": "console", "formatter": {"type": "xml"}}]}`, "handlers[0].formatter.type"},
+		{`{"handlers": [{"type": "console"}, {"type": "console"}]}`, "handlers[1].name"},
+		{`{"handlers": [{"type": "console", "processors": ["invalid"]}]}`, "handlers[0].processors[0]"},
+	}
+
+	for _, tt := range tests {
+		c, err := handler.LoggerConfigFromJSON([]byte(tt.json))
+		assert.NoErr(t, err)
+
+		err = c.Validate()
+		var ce *handler.ConfigError
+		assert.True(t, errors.As(err, &ce), tt.json)
+		assert.Eq(t, tt.path, ce.Path, tt.json)
+		assert.StrContains(t, err.Error(), "slog: invalid config "+tt.path+": ")
+	}
+
+	// error on create handler
+	c, err := handler.LoggerConfigFromJSON([]byte(`{"handlers": [{"type": "console"}, {"type": "file", "options": {"logfile": ""}}]}`))
+	assert.NoErr(t, err)
+	_, err = c.NewLogger()
+	assert.ErrMsg(t, err, "slog: invalid config handlers[1].options.logfile: cannot be empty")
+
+	handler.RegisterHandlerType("test_no_processable", func(hc *handler.HandlerConfig, lf slog.LevelFormattable) (slog.Handler, error) {
+		return handler.NewSamplingHandler(handler.NewConsoleWithLF(lf), 1, 1), nil
+	})
+	c, err = handler.LoggerConfigFromJSON([]byte(`{"handlers": [{"type": "test_no_processable", "processors": ["hostname"]}]}`))
+	assert.NoErr(t, err)
+	_, err = c.NewLogger()
+	assert.ErrMsg(t, err, `slog: invalid config handlers[0].processors: the handler type "test_no_processable" does not support processors`)
+
+	c, err = handler.LoggerConfigFromJSON([]byte(`{"handlers": [{"type": "file", "options": {"buff_size": "invalid"}}]}`))
+	assert.NoErr(t, err)
+	_, err = c.CreateHandlers()
+	assert.StrContains(t, err.Error(), "slog: invalid config handlers[0].options: ")
+
+	// unknown option key
+	c, err = handler.LoggerConfigFromJSON([]byte(`{"handlers": [{"type": "file", "options": {"logfile": "./testdata/unknown.log", "max_sise": 1024}}]}`))
+	assert.NoErr(t, err)
+	_, err = c.CreateHandlers()
+	assert.ErrMsg(t, err, "slog: invalid config handlers[0].options.max_sise: unknown option")
+
+	// the console, stdout, stderr have no options
+	for _, typ := range []string{"console", "stdout", "stderr"} {
+		c, err = handler.LoggerConfigFromJSON([]byte(`{"handlers": [{"type": "` + typ + `", "options": {"colour": true}}]}`))
+		assert.NoErr(t, err)
+		_, err = c.CreateHandlers()
+		assert.ErrMsg(t, err, "slog: invalid config handlers[0].options.colour: unknown option")
+	}
+}
+
+func TestLoggerConfig_ApplyTo(t *testing.T) {
+	l := slog.New()
+	c := &handler.LoggerConfig{GlobalFields: map[string]any{"app": "demo", "old": "value"}}
+	c.ApplyTo(l)
+	assert.Eq(t, map[string]any{"app": "demo", "old": "value"}, l.GlobalFields)
+
+	// the old global fields are removed
+	c.GlobalFields = map[string]any{"app": "demo2"}
+	c.ApplyTo(l)
+	assert.Eq(t, map[string]any{"app": "demo2"}, l.GlobalFields)
+	c.GlobalFields = nil
+	c.ApplyTo(l)
+	assert.Empty(t, l.GlobalFields)
+}
+
+func TestLoadLoggerConfig(t *testing.T) {
+	_, err := handler.LoadLoggerConfig("./testdata/not-exists.json")
+	assert.Err(t, err)
+
+	file := "./testdata/logger-config.ini"
+	_, err = fsutil.PutContents(file, "name=app")
+	assert.NoErr(t, err)
+	_, err = handler.LoadLoggerConfig(file)
+	assert.ErrMsg(t, err, `slog: no config decoder for the format "ini"`)
+
+	// custom decoder
+	handler.RegisterConfigDecoder(".ini", func(bs []byte, v any) error {
+		c := v.(*handler.LoggerConfig)
+		c.Name = strings.TrimPrefix(string(bs), "name=")
+		return nil
+	})
+	l, err := handler.NewLoggerFromFile(file)
+	assert.NoErr(t, err)
+	assert.Eq(t, "app", l.Name())
+
+	_, err = handler.ParseLoggerConfig([]byte("{invalid"), "json")
+	assert.StrContains(t, err.Error(), "slog: decode json config: ")
+
+	// yaml is supported by default
+	c, err := handler.ParseLoggerConfig([]byte(`
+name: app
+level: warn
+global_fields:
+  app: demo
+handlers:
+  - type: file
+    formatter:
+      type: json
+    options:
+      logfile: ./testdata/logger-config-yaml.log
+      buff_size: 1024
+`), "yaml")
+	assert.NoErr(t, err)
+	assert.Eq(t, "app", c.Name)
+	assert.Eq(t, "warn", c.Level)
+	assert.Eq(t, map[string]any{"app": "demo"}, c.GlobalFields)
+	assert.Len(t, c.Handlers, 1)
+	assert.Eq(t, "json", c.Handlers[0].Formatter.Type)
+
+	l, err = c.NewLogger()
+	assert.NoErr(t, err)
+	assert.Eq(t, 1, l.HandlersNum())
+	assert.NoErr(t, l.Close())
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// DefaultReloadInterval the default interval for check the config file changes
+const DefaultReloadInterval = 2 * time.Second
+
+// Reloader reload the handlers of the logger by the LoggerConfig file without restart.
+//
+// The reload is triggered by:
+//   - the config file changed, check by stat polling. see Interval
+//   - receive the SIGHUP signal. not supported on windows
+//   - call Reload() explicitly
+//
+// On reload, the new handlers are created and swapped into the logger atomically,
+// then the old handlers are flushed and closed. If the new config is invalid,
+// the current handlers stay in place.
+//
+// NOTE: only the handlers and the logger level will be reloaded, other logger options
+// (eg: channel, processors, global fields) need restart to take effect.
+//
+// Usage:
+//
+//	l, err := handler.NewLoggerFromFile(file)
+//	rl := handler.NewReloader(l, file)
+//	rl.Start()
+//	defer rl.Stop()
+type Reloader struct {
+	logger *slog.Logger
+	file   string
+
+	mu sync.Mutex
+	// stat of the config file on last reload
+	modTime time.Time
+	size    int64
+	// reload times
+	reloads int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	// Interval for check the config file changes. default is DefaultReloadInterval
+	//
+	// set to < 0 for disable the stat polling.
+	Interval time.Duration
+	// WatchSignal reload on receive the SIGHUP signal. default is true
+	WatchSignal bool
+	// OnReload hook on reload success
+	OnReload func(c *LoggerConfig)
+	// OnError hook on reload failed, or close the old handlers failed.
+	// default will print the error to stderr.
+	OnError func(err error)
+}
+
+// NewReloader create a new Reloader for the logger and config file
+func NewReloader(l *slog.Logger, file string, fns ...func(r *Reloader)) *Reloader {
+	r := &Reloader{
+		logger: l,
+		file:   file,
+		// options
+		Interval:    DefaultReloadInterval,
+		WatchSignal: true,
+	}
+
+	for _, fn := range fns {
+		fn(r)
+	}
+	return r
+}
+
+// File get the config file path
+func (r *Reloader) File() string { return r.file }
+
+// Reloads get the number of the successful reloads
+func (r *Reloader) Reloads() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reloads
+}
+
+// Reload the config file and swap the handlers of the logger.
+//
+// If the config is invalid, will return error and the current handlers stay in place.
+func (r *Reloader) Reload() error {
+	c, old, err := r.swap()
+	if err != nil {
+		return err
+	}
+
+	for _, h := range old {
+		if err := h.Flush(); err != nil {
+			r.onError(fmt.Errorf("slog: flush the old handler error: %w", err))
+		}
+		if err := h.Close(); err != nil {
+			r.onError(fmt.Errorf("slog: close the old handler error: %w", err))
+		}
+	}
+
+	if r.OnReload != nil {
+		r.OnReload(c)
+	}
+	return nil
+}
+
+// load the config file and swap the handlers, returns the old handlers.
+func (r *Reloader) swap() (*LoggerConfig, []slog.Handler, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// record the stat before read, so the changes on reading can be detected next time.
+	fi, err := os.Stat(r.file)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.modTime, r.size = fi.ModTime(), fi.Size()
+
+	c, err := LoadLoggerConfig(r.file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hs, err := c.CreateHandlers()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the level is validated. empty will reset the max level
+	var lv slog.Level
+	if c.Level != "" {
+		lv, _ = parseLevel(c.Level)
+	}
+	old := r.logger.ReplaceHandlersLevel(hs, lv)
+
+	r.reloads++
+	return c, old, nil
+}
+
+// Start watch the config file changes and the SIGHUP signal in background.
+func (r *Reloader) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stop != nil {
+		return
+	}
+
+	// the current handlers are created by the current config file
+	if fi, err := os.Stat(r.file); err == nil {
+		r.modTime, r.size = fi.ModTime(), fi.Size()
+	}
+
+	var sigCh chan os.Signal
+	if r.WatchSignal {
+		sigCh = make(chan os.Signal, 1)
+		notifyReload(sigCh)
+	}
+
+	interval := r.Interval
+	if interval == 0 {
+		interval = DefaultReloadInterval
+	}
+
+	r.stop = make(chan struct{})
+	r.wg.Add(1)
+	go r.watch(r.stop, sigCh, interval)
+}
+
+// Stop watch the config file changes and the signal. it is safe to call multiple times.
+func (r *Reloader) Stop() {
+	r.mu.Lock()
+	if r.stop == nil {
+		r.mu.Unlock()
+		return
+	}
+	close(r.stop)
+	r.stop = nil
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
+
+func (r *Reloader) watch(stop chan struct{}, sigCh chan os.Signal, interval time.Duration) {
+	defer r.wg.Done()
+	if sigCh != nil {
+		defer stopNotifyReload(sigCh)
+	}
+
+	var tickCh <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tickCh = ticker.C
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-sigCh:
+			r.reload()
+		case <-tickCh:
+			if r.changed() {
+				r.reload()
+			}
+		}
+	}
+}
+
+func (r *Reloader) reload() {
+	if err := r.Reload(); err != nil {
+		r.onError(err)
+	}
+}
+
+// check the config file is changed since last reload. stat error is ignored, eg: the file is being replaced.
+func (r *Reloader) changed() bool {
+	fi, err := os.Stat(r.file)
+	if err != nil {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !fi.ModTime().Equal(r.modTime) || fi.Size() != r.size
+}
+
+func (r *Reloader) onError(err error) {
+	if r.OnError != nil {
+		r.OnError(err)
+	} else {
+		_, _ = fmt.Fprintln(os.Stderr, "slog: reload logger config error:", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !windows && !plan9
+
+package handler
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notify the SIGHUP signal for reload
+func notifyReload(ch chan<- os.Signal) { signal.Notify(ch, syscall.SIGHUP) }
+
+func stopNotifyReload(ch chan<- os.Signal) { signal.Stop(ch) }
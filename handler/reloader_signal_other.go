@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package handler
+
+import "os"
+
+// the SIGHUP signal is not supported on the platform
+func notifyReload(ch chan<- os.Signal) {}
+
+func stopNotifyReload(ch chan<- os.Signal) {}
@@ -0,0 +1,37 @@
+//go:build !windows && !plan9
+
+package handler_test
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/handler"
+)
+
+func TestReloader_signal(t *testing.T) {
+	cfgFile := "./testdata/reload-signal.json"
+	writeReloadConfig(t, cfgFile, "info", "./testdata/reload-signal.log")
+
+	l, err := handler.NewLoggerFromFile(cfgFile)
+	assert.NoErr(t, err)
+	defer l.MustClose()
+
+	reloaded := make(chan struct{}, 1)
+	rl := handler.NewReloader(l, cfgFile, func(r *handler.Reloader) {
+		r.Interval = -1
+		r.OnReload = func(c *handler.LoggerConfig) { reloaded <- struct{}{} }
+	})
+	rl.Start()
+	defer rl.Stop()
+
+	assert.NoErr(t, syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+	select {
+	case <-reloaded:
+		assert.Eq(t, 1, rl.Reloads())
+	case <-time.After(3 * time.Second):
+		t.Fatal("not reloaded on the SIGHUP signal")
+	}
+}
@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog/handler"
+)
+
+func writeReloadConfig(t *testing.T, file, level, logfile string) {
+	_, err := fsutil.PutContents(file, fmt.Sprintf(`{
+	"level": %q,
+	"handlers": [{
+		"type": "file",
+		"formatter": {"template": "{{level}} {{message}}\n"},
+		"options": {"logfile": %q}
+	}]
+}`, level, logfile))
+	assert.NoErr(t, err)
+}
+
+func TestReloader_Reload(t *testing.T) {
+	cfgFile := "./testdata/reload-config.json"
+	writeReloadConfig(t, cfgFile, "info", "./testdata/reload-before.log")
+
+	l, err := handler.NewLoggerFromFile(cfgFile)
+	assert.NoErr(t, err)
+
+	var reloaded int
+	var errs []error
+	rl := handler.NewReloader(l, cfgFile, func(r *handler.Reloader) {
+		r.OnReload = func(c *handler.LoggerConfig) { reloaded++ }
+		r.OnError = func(err error) { errs = append(errs, err) }
+	})
+	assert.Eq(t, cfgFile, rl.File())
+
+	l.Debug("debug before")
+	l.Info("info before")
+
+	writeReloadConfig(t, cfgFile, "warn", "./testdata/reload-after.log")
+	assert.NoErr(t, rl.Reload())
+	assert.Eq(t, 1, reloaded)
+	assert.Eq(t, 1, rl.Reloads())
+	assert.Eq(t, 1, l.HandlersNum())
+
+	l.Info("info after")
+	l.Warn("warn after")
+
+	// invalid config, the current handlers stay in place
+	_, err = fsutil.PutContents(cfgFile, `{"handlers": [{"type": "not-exists"}]}`)
+	assert.NoErr(t, err)
+	err = rl.Reload()
+	var ce *handler.ConfigError
+	assert.True(t, errors.As(err, &ce))
+	assert.Eq(t, "handlers[0].type", ce.Path)
+	assert.Eq(t, 1, rl.Reloads())
+
+	l.Error("error after")
+	assert.NoErr(t, l.Close())
+	assert.Empty(t, errs)
+
+	bs, err := os.ReadFile("./testdata/reload-before.log")
+	assert.NoErr(t, err)
+	assert.Eq(t, "INFO info before\n", string(bs))
+
+	bs, err = os.ReadFile("./testdata/reload-after.log")
+	assert.NoErr(t, err)
+	assert.Eq(t, "WARNING warn after\nERROR error after\n", string(bs))
+
+	// config file not exists
+	rl = handler.NewReloader(l, "./testdata/not-exists.json")
+	assert.Err(t, rl.Reload())
+}
+
+func TestReloader_Start(t *testing.T) {
+	cfgFile := "./testdata/reload-watch.json"
+	writeReloadConfig(t, cfgFile, "info", "./testdata/reload-watch1.log")
+
+	l, err := handler.NewLoggerFromFile(cfgFile)
+	assert.NoErr(t, err)
+
+	reloaded := make(chan *handler.LoggerConfig, 1)
+	rl := handler.NewReloader(l, cfgFile, func(r *handler.Reloader) {
+		r.Interval = 10 * time.Millisecond
+		r.WatchSignal = false
+		r.OnReload = func(c *handler.LoggerConfig) { reloaded <- c }
+	})
+	rl.Start()
+	rl.Start() // repeat start is ignored
+	defer rl.Stop()
+
+	// logging on reloading
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				l.Info("info message")
+			}
+		}
+	}()
+
+	writeReloadConfig(t, cfgFile, "warn", "./testdata/reload-watch2.log")
+	select {
+	case c := <-reloaded:
+		assert.Eq(t, "warn", c.Level)
+	case <-time.After(3 * time.Second):
+		t.Fatal("the config file changes is not reloaded")
+	}
+
+	close(stop)
+	wg.Wait()
+	rl.Stop()
+	rl.Stop()
+
+	l.Warn("warn message")
+	assert.NoErr(t, l.Close())
+
+	bs, err := os.ReadFile("./testdata/reload-watch2.log")
+	assert.NoErr(t, err)
+	assert.Eq(t, "WARNING warn message\n", string(bs))
+}
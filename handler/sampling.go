@@ -0,0 +1,278 @@
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// DefaultSamplingInterval default interval for reset the sampling counters
+var DefaultSamplingInterval = time.Second
+
+// DefaultSamplingMaxKeys default max number of the counted keys per interval
+var DefaultSamplingMaxKeys = 1000
+
+// SamplingRule the sampling rule. see SamplingHandler
+type SamplingRule struct {
+	// First pass the first N records of each key per interval.
+	//
+	// If First <= 0, the records will not be sampled, all passed.
+	First int
+	// Thereafter pass every Mth record after the First. if <= 0, drop all after the First.
+	Thereafter int
+}
+
+// pass check the nth(start from 1) record is passed.
+func (sr SamplingRule) pass(n uint64) bool {
+	if sr.First <= 0 || n <= uint64(sr.First) {
+		return true
+	}
+	return sr.Thereafter > 0 && (n-uint64(sr.First))%uint64(sr.Thereafter) == 0
+}
+
+// the sampling key: level + message
+type samplingKey struct {
+	level slog.Level
+	msg   string
+	// the shared key of the level for the new messages on the counters is full
+	other bool
+}
+
+// SamplingHandler wrap a handler, sampling the records by (level, message) key per interval.
+//
+// In each interval, pass the first N records of each key, and then every Mth record.
+// The Panic and Fatal level records are never dropped.
+//
+// Usage:
+//
+//	// pass the first 100 records per second, and then every 10th.
+//	h := handler.NewSamplingHandler(fileHandler, 100, 10)
+//	l := slog.NewWithHandlers(h)
+type SamplingHandler struct {
+	// Handler the wrapped handler
+	Handler slog.Handler
+	// Rule the default sampling rule for all levels
+	Rule SamplingRule
+	// LevelRules custom sampling rule by level. eg: {slog.DebugLevel: {First: 10}}
+	LevelRules map[slog.Level]SamplingRule
+	// Interval for reset the counters. default is DefaultSamplingInterval
+	Interval time.Duration
+	// MaxKeys max number of the counted keys per interval. default is DefaultSamplingMaxKeys
+	//
+	// On full, the records of the new messages share one counter per level.
+	MaxKeys int
+	// EmitSummary emit a summary record of the dropped records to the Handler, on the interval is over.
+	//
+	// TIP: the summary is emitted on handle the next record after the interval, or call Flush, Close.
+	EmitSummary bool
+	// SummaryLevel the level of the summary record. default is slog.WarnLevel
+	SummaryLevel slog.Level
+	// TimeClock custom time clock. default is slog.DefaultClockFn
+	TimeClock slog.ClockFn
+
+	mu sync.Mutex
+	// start time of current interval
+	start    time.Time
+	counters map[samplingKey]uint64
+	// dropped records of current interval, by level
+	intervalDropped map[slog.Level]uint64
+	// total dropped records, by level
+	dropped map[slog.Level]uint64
+	channel string
+}
+
+// NewSamplingHandler create a new SamplingHandler. pass the first N records per interval, and then every Mth.
+func NewSamplingHandler(h slog.Handler, first, thereafter int, fns ...func(h *SamplingHandler)) *SamplingHandler {
+	sh := &SamplingHandler{
+		Handler:      h,
+		Rule:         SamplingRule{First: first, Thereafter: thereafter},
+		Interval:     DefaultSamplingInterval,
+		MaxKeys:      DefaultSamplingMaxKeys,
+		SummaryLevel: slog.WarnLevel,
+		TimeClock:    slog.DefaultClockFn,
+		// init maps
+		counters:        make(map[samplingKey]uint64),
+		intervalDropped: make(map[slog.Level]uint64),
+		dropped:         make(map[slog.Level]uint64),
+	}
+
+	for _, fn := range fns {
+		fn(sh)
+	}
+	return sh
+}
+
+// IsHandling check level is enabled by the wrapped handler
+func (h *SamplingHandler) IsHandling(level slog.Level) bool {
+	return h.Handler.IsHandling(level)
+}
+
+// Handle log record, the dropped record will return nil.
+func (h *SamplingHandler) Handle(r *slog.Record) error {
+	h.mu.Lock()
+	h.initMaps()
+	summary := h.rotate(h.TimeClock.Now())
+	h.channel = r.Channel
+	passed := h.sample(r)
+	h.mu.Unlock()
+
+	if summary != nil {
+		if err := h.Handler.Handle(summary); err != nil {
+			return err
+		}
+	}
+
+	if passed {
+		return h.Handler.Handle(r)
+	}
+	return nil
+}
+
+// sample check the record is passed, must be called in lock.
+func (h *SamplingHandler) sample(r *slog.Record) bool {
+	// never drop the panic, fatal
+	if r.Level <= slog.FatalLevel {
+		return true
+	}
+
+	rule, ok := h.LevelRules[r.Level]
+	if !ok {
+		rule = h.Rule
+	}
+	if rule.First <= 0 {
+		return true
+	}
+
+	key := samplingKey{level: r.Level, msg: r.Message}
+	if _, ok := h.counters[key]; !ok && len(h.counters) >= h.maxKeys() {
+		key = samplingKey{level: r.Level, other: true}
+	}
+
+	n := h.counters[key] + 1
+	h.counters[key] = n
+
+	if rule.pass(n) {
+		return true
+	}
+
+	h.intervalDropped[r.Level]++
+	h.dropped[r.Level]++
+	return false
+}
+
+// init the maps on the handler is created without NewSamplingHandler(), must be called in lock.
+func (h *SamplingHandler) initMaps() {
+	if h.counters == nil {
+		h.counters = make(map[samplingKey]uint64)
+		h.intervalDropped = make(map[slog.Level]uint64)
+		h.dropped = make(map[slog.Level]uint64)
+	}
+}
+
+func (h *SamplingHandler) maxKeys() int {
+	if h.MaxKeys > 0 {
+		return h.MaxKeys
+	}
+	return DefaultSamplingMaxKeys
+}
+
+// rotate to new interval if the current is over. returns the summary record of previous interval.
+func (h *SamplingHandler) rotate(now time.Time) *slog.Record {
+	interval := h.Interval
+	if interval <= 0 {
+		interval = DefaultSamplingInterval
+	}
+
+	if h.start.IsZero() {
+		h.start = now
+		return nil
+	}
+	if now.Sub(h.start) < interval {
+		return nil
+	}
+
+	h.start = now
+	for k := range h.counters {
+		delete(h.counters, k)
+	}
+	return h.takeSummary(now)
+}
+
+// take the summary record of the dropped records in current interval, must be called in lock.
+func (h *SamplingHandler) takeSummary(now time.Time) *slog.Record {
+	if len(h.intervalDropped) == 0 {
+		return nil
+	}
+
+	var total uint64
+	levels := make(slog.M, len(h.intervalDropped))
+	for lv, n := range h.intervalDropped {
+		total += n
+		levels[lv.Name()] = n
+		delete(h.intervalDropped, lv)
+	}
+
+	if !h.EmitSummary || !h.Handler.IsHandling(h.SummaryLevel) {
+		return nil
+	}
+
+	r := &slog.Record{
+		Time:    now,
+		Level:   h.SummaryLevel,
+		Channel: h.channel,
+		Message: "slog: sampling dropped records",
+		Data:    slog.M{"dropped": total, "levels": levels},
+	}
+	if r.Channel == "" {
+		r.Channel = slog.DefaultChannelName
+	}
+	r.Init(false)
+	return r
+}
+
+// Dropped get the total number of dropped records
+func (h *SamplingHandler) Dropped() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var total uint64
+	for _, n := range h.dropped {
+		total += n
+	}
+	return total
+}
+
+// DroppedOf get the number of dropped records of the level
+func (h *SamplingHandler) DroppedOf(level slog.Level) uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.dropped[level]
+}
+
+// Flush the wrapped handler. will emit the summary of current interval if EmitSummary is true.
+func (h *SamplingHandler) Flush() error {
+	if err := h.emitSummary(); err != nil {
+		return err
+	}
+	return h.Handler.Flush()
+}
+
+// Close the wrapped handler. will emit the summary of current interval if EmitSummary is true.
+func (h *SamplingHandler) Close() error {
+	if err := h.emitSummary(); err != nil {
+		return err
+	}
+	return h.Handler.Close()
+}
+
+func (h *SamplingHandler) emitSummary() error {
+	h.mu.Lock()
+	summary := h.takeSummary(h.TimeClock.Now())
+	h.mu.Unlock()
+
+	if summary != nil {
+		return h.Handler.Handle(summary)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package handler_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/slogtest"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wh := handler.NewSimple(buf, slog.TraceLevel)
+	wh.SetFormatter(slog.NewTextFormatter("{{level}} {{message}} {{data}}\n"))
+
+	now := time.Now()
+	h := handler.NewSamplingHandler(wh, 2, 3, func(h *handler.SamplingHandler) {
+		h.EmitSummary = true
+		h.TimeClock = func() time.Time { return now }
+	})
+	assert.True(t, h.IsHandling(slog.InfoLevel))
+
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+
+	// 1,2 passed, then every 3rd: 5,8
+	for i := 0; i < 9; i++ {
+		l.Info("info message")
+	}
+	assert.Eq(t, 4, strings.Count(buf.String(), "info message"))
+	assert.Eq(t, uint64(5), h.Dropped())
+	assert.Eq(t, uint64(5), h.DroppedOf(slog.InfoLevel))
+
+	// other key is counted separately
+	l.Info("other message")
+	l.Warn("info message")
+	assert.StrContains(t, buf.String(), "INFO other message")
+	assert.StrContains(t, buf.String(), "WARNING info message")
+	buf.Reset()
+
+	// next interval: emit the summary and reset counters
+	now = now.Add(time.Second)
+	l.Info("info message")
+	s := buf.String()
+	assert.StrContains(t, s, "WARNING slog: sampling dropped records")
+	assert.StrContains(t, s, "dropped:5")
+	assert.StrContains(t, s, "INFO info message")
+	buf.Reset()
+
+	// never drop panic, fatal
+	for i := 0; i < 5; i++ {
+		l.Panic("panic message")
+	}
+	assert.Eq(t, 5, strings.Count(buf.String(), "panic message"))
+
+	// no dropped, no summary
+	buf.Reset()
+	assert.NoErr(t, h.Flush())
+	assert.Empty(t, buf.String())
+	assert.Eq(t, uint64(5), h.Dropped())
+}
+
+func TestSamplingHandler_LevelRules(t *testing.T) {
+	buf := new(bytes.Buffer)
+	wh := handler.NewSimple(buf, slog.TraceLevel)
+	wh.SetFormatter(slog.NewTextFormatter("{{level}} {{message}} {{data}}\n"))
+
+	h := handler.NewSamplingHandler(wh, 0, 0, func(h *handler.SamplingHandler) {
+		h.EmitSummary = true
+		h.LevelRules = map[slog.Level]handler.SamplingRule{
+			slog.DebugLevel: {First: 1},
+		}
+	})
+
+	l := slog.NewWithHandlers(h)
+	for i := 0; i < 3; i++ {
+		l.Info("info message")
+		l.Debug("debug message")
+	}
+	assert.Eq(t, 3, strings.Count(buf.String(), "info message"))
+	assert.Eq(t, 1, strings.Count(buf.String(), "debug message"))
+	assert.Eq(t, uint64(0), h.DroppedOf(slog.InfoLevel))
+	assert.Eq(t, uint64(2), h.DroppedOf(slog.DebugLevel))
+	buf.Reset()
+
+	// emit summary on close
+	assert.NoErr(t, h.Close())
+	s := buf.String()
+	assert.StrContains(t, s, "WARNING slog: sampling dropped records {")
+	assert.StrContains(t, s, "dropped:2")
+	assert.StrContains(t, s, "levels:{DEBUG:2}")
+}
+
+func TestSamplingHandler_MaxKeys(t *testing.T) {
+	rh := slogtest.NewRecordingHandler()
+	// create without the constructor
+	h := &handler.SamplingHandler{
+		Handler: rh,
+		Rule:    handler.SamplingRule{First: 1},
+		MaxKeys: 2,
+	}
+
+	l := slog.NewWithHandlers(h)
+	for i := 0; i < 2; i++ {
+		l.Info("message1")
+		l.Info("message2")
+		// the new messages share one counter on full
+		l.Info("message3")
+		l.Info("message4")
+	}
+	assert.Eq(t, []string{"message1", "message2", "message3"}, rh.Messages())
+	assert.Eq(t, uint64(5), h.Dropped())
+}
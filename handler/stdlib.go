@@ -0,0 +1,152 @@
+//go:build go1.21
+
+package handler
+
+import (
+	"context"
+	"io"
+	stdslog "log/slog"
+	"sort"
+
+	"github.com/gookit/slog"
+)
+
+// StdlibHandler wrap a Go standard library log/slog.Handler as slog.Handler.
+//
+//   - Record.Level is mapped by slog.LevelToStdlib()
+//   - Record.Fields are added as top level attrs
+//   - Record.Data, Record.Extra are added as groups. see DataKey, ExtraKey
+//   - Record.Caller is used as the source position, Record.Ctx is passed to the wrapped handler.
+//
+// Usage:
+//
+//	h := handler.NewStdlibHandler(stdslog.NewJSONHandler(os.Stdout, nil))
+//	l := slog.NewWithHandlers(h)
+type StdlibHandler struct {
+	// Handler the wrapped std handler
+	Handler stdslog.Handler
+	// DataKey the group name for Record.Data. default is slog.FieldKeyData
+	//
+	// set to empty for add the data as top level attrs.
+	DataKey string
+	// ExtraKey the group name for Record.Extra. default is slog.FieldKeyExtra
+	//
+	// set to empty for add the extra as top level attrs.
+	ExtraKey string
+}
+
+// NewStdlibHandler create a new StdlibHandler
+func NewStdlibHandler(h stdslog.Handler, fns ...func(h *StdlibHandler)) *StdlibHandler {
+	sh := &StdlibHandler{
+		Handler:  h,
+		DataKey:  slog.FieldKeyData,
+		ExtraKey: slog.FieldKeyExtra,
+	}
+
+	for _, fn := range fns {
+		fn(sh)
+	}
+	return sh
+}
+
+// IsHandling check level is enabled by the wrapped handler
+func (h *StdlibHandler) IsHandling(level slog.Level) bool {
+	return h.Handler.Enabled(context.Background(), slog.LevelToStdlib(level))
+}
+
+// Handle log record
+func (h *StdlibHandler) Handle(r *slog.Record) error {
+	var pc uintptr
+	if r.Caller != nil {
+		// Frame.PC is the call instruction, the std handler expects the return address.
+		pc = r.Caller.PC + 1
+	}
+
+	sr := stdslog.NewRecord(r.Time, slog.LevelToStdlib(r.Level), r.Message, pc)
+	sr.AddAttrs(mapToAttrs(r.Fields)...)
+	sr.AddAttrs(fieldsToAttrs(r.TypedFields())...)
+	h.addGroup(&sr, h.DataKey, r.Data)
+	h.addGroup(&sr, h.ExtraKey, r.Extra)
+
+	ctx := r.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return h.Handler.Handle(ctx, sr)
+}
+
+func (h *StdlibHandler) addGroup(sr *stdslog.Record, key string, m slog.M) {
+	if len(m) == 0 {
+		return
+	}
+
+	if key == "" {
+		sr.AddAttrs(mapToAttrs(m)...)
+	} else {
+		sr.AddAttrs(stdslog.Attr{Key: key, Value: stdslog.GroupValue(mapToAttrs(m)...)})
+	}
+}
+
+// Flush the wrapped handler, if it has Flush() method.
+func (h *StdlibHandler) Flush() error {
+	if fh, ok := h.Handler.(interface{ Flush() error }); ok {
+		return fh.Flush()
+	}
+	return nil
+}
+
+// Close the wrapped handler, if it has Close() method.
+func (h *StdlibHandler) Close() error {
+	if ch, ok := h.Handler.(io.Closer); ok {
+		return ch.Close()
+	}
+	return nil
+}
+
+// convert map to attrs, sorted by key. the nested map will be converted to group.
+func mapToAttrs(m map[string]any) []stdslog.Attr {
+	if len(m) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]stdslog.Attr, 0, len(m))
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case slog.M:
+			attrs = append(attrs, stdslog.Attr{Key: k, Value: stdslog.GroupValue(mapToAttrs(v)...)})
+		case map[string]any:
+			attrs = append(attrs, stdslog.Attr{Key: k, Value: stdslog.GroupValue(mapToAttrs(v)...)})
+		default:
+			attrs = append(attrs, stdslog.Any(k, v))
+		}
+	}
+	return attrs
+}
+
+// convert typed fields to attrs, the Dict will be converted to group.
+func fieldsToAttrs(fs []slog.Field) []stdslog.Attr {
+	if len(fs) == 0 {
+		return nil
+	}
+
+	attrs := make([]stdslog.Attr, 0, len(fs))
+	for i := 0; i < len(fs); i++ {
+		f := &fs[i]
+		switch f.Kind {
+		case slog.FieldKindDict:
+			attrs = append(attrs, stdslog.Attr{Key: f.Key, Value: stdslog.GroupValue(fieldsToAttrs(fs[i+1 : i+1+f.Span()])...)})
+		case slog.FieldKindArray:
+			attrs = append(attrs, stdslog.Any(f.Key, slog.FieldsToMap(fs[i : i+1+f.Span()])[f.Key]))
+		default:
+			attrs = append(attrs, stdslog.Any(f.Key, f.Value()))
+		}
+		i += f.Span()
+	}
+	return attrs
+}
@@ -0,0 +1,81 @@
+//go:build go1.21
+
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stdslog "log/slog"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+type ctxKey string
+
+// ctxHandler add the context value to the attrs
+type ctxHandler struct {
+	stdslog.Handler
+}
+
+func (h ctxHandler) Handle(ctx context.Context, r stdslog.Record) error {
+	if v := ctx.Value(ctxKey("req_id")); v != nil {
+		r.AddAttrs(stdslog.Any("req_id", v))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func TestStdlibHandler(t *testing.T) {
+	buf := new(bytes.Buffer)
+	sh := stdslog.NewJSONHandler(buf, &stdslog.HandlerOptions{
+		AddSource: true,
+		Level:     stdslog.LevelInfo,
+	})
+
+	h := handler.NewStdlibHandler(ctxHandler{sh})
+	assert.True(t, h.IsHandling(slog.InfoLevel))
+	assert.True(t, h.IsHandling(slog.ErrorLevel))
+	assert.False(t, h.IsHandling(slog.DebugLevel))
+
+	l := slog.NewWithHandlers(h)
+	l.Debug("debug message")
+	assert.Empty(t, buf.String())
+
+	ctx := context.WithValue(context.Background(), ctxKey("req_id"), "abc")
+	l.WithField("app", "demo").
+		WithData(slog.M{"user": slog.M{"id": 23}}).
+		AddExtra(slog.M{"ext": "val"}).
+		SetContext(ctx).
+		Warn("warn message")
+
+	out := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Eq(t, "WARN", out["level"])
+	assert.Eq(t, "warn message", out["msg"])
+	assert.Eq(t, "demo", out["app"])
+	assert.Eq(t, "abc", out["req_id"])
+	assert.Eq(t, map[string]any{"user": map[string]any{"id": float64(23)}}, out["data"])
+	assert.Eq(t, map[string]any{"ext": "val"}, out["extra"])
+	assert.StrContains(t, out["source"].(map[string]any)["file"].(string), "stdlib_test.go")
+
+	// add data as top level attrs
+	buf.Reset()
+	h = handler.NewStdlibHandler(sh, func(h *handler.StdlibHandler) {
+		h.DataKey = ""
+	})
+	l = slog.NewWithHandlers(h)
+	l.ReportCaller = false
+	l.WithData(slog.M{"key": "val"}).Error("error message")
+
+	out = map[string]any{}
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &out))
+	assert.Eq(t, "ERROR", out["level"])
+	assert.Eq(t, "val", out["key"])
+	assert.NotContains(t, out, "source")
+
+	assert.NoErr(t, h.Flush())
+	assert.NoErr(t, h.Close())
+}
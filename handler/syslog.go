@@ -23,6 +23,7 @@ type SysLogOpt struct {
 // SysLogHandler struct
 type SysLogHandler struct {
 	slog.LevelWithFormatter
+	slog.Processable
 	writer *syslog.Writer
 }
 
@@ -7,6 +7,7 @@ import (
 // FlushCloseHandler definition
 type FlushCloseHandler struct {
 	slog.LevelFormattable
+	slog.Processable
 	Output FlushCloseWriter
 }
 
@@ -70,11 +71,5 @@ func (h *FlushCloseHandler) Flush() error {
 
 // Handle log record
 func (h *FlushCloseHandler) Handle(record *slog.Record) error {
-	bts, err := h.Formatter().Format(record)
-	if err != nil {
-		return err
-	}
-
-	_, err = h.Output.Write(bts)
-	return err
+	return slog.FormatTo(h.Output, h.Formatter(), record)
 }
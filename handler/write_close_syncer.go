@@ -9,6 +9,7 @@ import (
 // SyncCloseHandler definition
 type SyncCloseHandler struct {
 	slog.LevelFormattable
+	slog.Processable
 	Output SyncCloseWriter
 }
 
@@ -74,11 +75,5 @@ func (h *SyncCloseHandler) Writer() io.Writer {
 
 // Handle log record
 func (h *SyncCloseHandler) Handle(record *slog.Record) error {
-	bts, err := h.Formatter().Format(record)
-	if err != nil {
-		return err
-	}
-
-	_, err = h.Output.Write(bts)
-	return err
+	return slog.FormatTo(h.Output, h.Formatter(), record)
 }
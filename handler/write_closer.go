@@ -9,6 +9,7 @@ import (
 // WriteCloserHandler definition
 type WriteCloserHandler struct {
 	slog.LevelFormattable
+	slog.Processable
 	Output io.WriteCloser
 }
 
@@ -67,11 +68,5 @@ func (h *WriteCloserHandler) Flush() error {
 
 // Handle log record
 func (h *WriteCloserHandler) Handle(record *slog.Record) error {
-	bts, err := h.Formatter().Format(record)
-	if err != nil {
-		return err
-	}
-
-	_, err = h.Output.Write(bts)
-	return err
+	return slog.FormatTo(h.Output, h.Formatter(), record)
 }
@@ -10,6 +10,7 @@ import (
 type IOWriterHandler struct {
 	NopFlushClose
 	slog.LevelFormattable
+	slog.Processable
 	Output io.Writer
 }
 
@@ -20,13 +21,7 @@ func (h *IOWriterHandler) TextFormatter() *slog.TextFormatter {
 
 // Handle log record
 func (h *IOWriterHandler) Handle(record *slog.Record) error {
-	bts, err := h.Formatter().Format(record)
-	if err != nil {
-		return err
-	}
-
-	_, err = h.Output.Write(bts)
-	return err
+	return slog.FormatTo(h.Output, h.Formatter(), record)
 }
 
 // NewIOWriterWithLF create new IOWriterHandler, with custom slog.LevelFormattable
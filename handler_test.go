@@ -1,6 +1,8 @@
 package slog_test
 
 import (
+	"io"
+	"sync"
 	"testing"
 
 	"github.com/gookit/goutil/testutil/assert"
@@ -68,3 +70,100 @@ func TestLevelFormatting(t *testing.T) {
 	assert.Eq(t, "max", slog.LevelModeMax.String())
 	assert.Eq(t, "unknown", slog.LevelMode(9).String())
 }
+
+func TestLevelVar_shared(t *testing.T) {
+	lv := slog.NewLevelVar(slog.InfoLevel)
+	lf := slog.NewLvVarFormatter(lv)
+	lf2 := slog.NewLevelVarFormatting(lv)
+	sl := slog.NewSugared(io.Discard, slog.InfoLevel)
+	sl.SetLevelVar(lv)
+
+	assert.True(t, lf.IsHandling(slog.InfoLevel))
+	assert.False(t, lf.IsHandling(slog.DebugLevel))
+	assert.False(t, lf2.IsHandling(slog.DebugLevel))
+	assert.False(t, sl.IsHandling(slog.DebugLevel))
+
+	lv.Set(slog.DebugLevel)
+	assert.True(t, lf.IsHandling(slog.DebugLevel))
+	assert.True(t, lf2.IsHandling(slog.DebugLevel))
+	assert.True(t, sl.IsHandling(slog.DebugLevel))
+
+	// set max level will update the LevelVar
+	lf.SetMaxLevel(slog.WarnLevel)
+	assert.Eq(t, slog.WarnLevel, lv.Level())
+	lf2.SetMaxLevel(slog.ErrorLevel)
+	assert.Eq(t, slog.ErrorLevel, lv.Level())
+	sl.SetLevel(slog.NoticeLevel)
+	assert.Eq(t, slog.NoticeLevel, lv.Level())
+	assert.Eq(t, slog.NoticeLevel, sl.GetLevel())
+	assert.Eq(t, slog.NoticeLevel, sl.Level)
+
+	// change level on logging
+	l := slog.NewWithHandlers(sl)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if i%2 == 0 {
+					lv.Set(slog.DebugLevel)
+				} else {
+					l.Debug("debug message")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSetLogLevel_concurrent(t *testing.T) {
+	defer slog.Reset()
+	slog.Configure(func(sl *slog.SugaredLogger) {
+		sl.Output = io.Discard
+		sl.Level = slog.InfoLevel
+		sl.ReportCaller = false
+	})
+	assert.Eq(t, slog.InfoLevel, slog.Std().GetLevel())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				switch i {
+				case 0:
+					slog.SetLogLevel(slog.DebugLevel)
+				case 1:
+					slog.SetLevelByName("warn")
+				default:
+					slog.Debug("debug message")
+					slog.Info("info message")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	slog.SetLogLevel(slog.ErrorLevel)
+	assert.False(t, slog.Std().IsHandling(slog.WarnLevel))
+
+	// the NewSugaredLogger has a LevelVar by default
+	sl := slog.NewSugaredLogger(io.Discard, slog.WarnLevel)
+	assert.NotNil(t, sl.LevelVar)
+	assert.Eq(t, slog.WarnLevel, sl.LevelVar.Level())
+
+	// the Level and LevelVar are kept in sync
+	sl.SetLevel(slog.ErrorLevel)
+	assert.Eq(t, slog.ErrorLevel, sl.Level)
+	sl.Config(func(sl *slog.SugaredLogger) {
+		sl.Level = slog.InfoLevel
+	})
+	assert.True(t, sl.IsHandling(slog.InfoLevel))
+	assert.Eq(t, slog.InfoLevel, sl.GetLevel())
+
+	sl.SetLevelVar(slog.NewLevelVar(slog.NoticeLevel))
+	assert.Eq(t, slog.NoticeLevel, sl.Level)
+	assert.False(t, sl.IsHandling(slog.InfoLevel))
+}
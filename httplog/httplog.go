@@ -0,0 +1,359 @@
+// Package httplog provide the net/http middleware for request-scoped logging and access logs.
+//
+// Usage:
+//
+//	mux := http.NewServeMux()
+//	h := httplog.New(func(o *httplog.Options) {
+//		o.Logger = logger
+//	})(mux)
+//
+//	// in the http handler
+//	slog.FromContext(r.Context()).Info("some message") // with the field request_id
+package httplog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// the access log formats
+const (
+	// FormatFields output the request info as structured fields
+	FormatFields = "fields"
+	// FormatCommon output as Apache common log format line
+	FormatCommon = "common"
+	// FormatCombined output as Apache combined log format line
+	FormatCombined = "combined"
+)
+
+// DefaultRequestIDHeader the default header name of the request ID
+const DefaultRequestIDHeader = "X-Request-Id"
+
+// MaxRequestIDLength the max length of the request ID from the client
+const MaxRequestIDLength = 128
+
+// Redacted the replacement of the redacted header and query values
+const Redacted = "[REDACTED]"
+
+// RequestIDKey the context key of the request ID
+var RequestIDKey = slog.NewContextKey[string]("request_id")
+
+// RequestID get the request ID from context
+func RequestID(r *http.Request) string {
+	id, _ := RequestIDKey.Value(r.Context())
+	return id
+}
+
+// Options for the middleware
+type Options struct {
+	// Logger for write logs. default is the std logger
+	Logger *slog.Logger
+	// Format of the access log. allow: FormatFields, FormatCommon, FormatCombined. default is FormatFields
+	Format string
+	// Message of the access log on the Format is FormatFields. default is "http request"
+	Message string
+	// RequestIDHeader the header name for propagate request ID. default is DefaultRequestIDHeader
+	//
+	// The request ID from the client only accept [A-Za-z0-9._-] and max length is MaxRequestIDLength,
+	// otherwise will generate a new one.
+	RequestIDHeader string
+	// RequestIDField the field name of the request ID. default is "request_id"
+	RequestIDField string
+	// GenRequestID custom generate request ID func. default generate 16 random bytes as hex
+	GenRequestID func() string
+	// StatusLevels the log level by the status class. key is the class. eg: 4 for 4xx
+	//
+	// default: 5xx is Error, 4xx is Warn, others is Info
+	StatusLevels map[int]slog.Level
+	// LogHeaders log the request headers on the Format is FormatFields.
+	LogHeaders bool
+	// RedactHeaders the header values will be redacted. default: Authorization, Cookie, Set-Cookie, Proxy-Authorization
+	RedactHeaders []string
+	// RedactQuery the query values will be redacted. eg: token, password
+	RedactQuery []string
+	// TrustProxy use the X-Forwarded-For, X-Real-Ip header as the client IP
+	TrustProxy bool
+	// Skip logging the access log. eg: skip the health check requests
+	Skip func(r *http.Request) bool
+}
+
+// NewOptions create a default options
+func NewOptions(fns ...func(o *Options)) *Options {
+	o := &Options{
+		Format:          FormatFields,
+		Message:         "http request",
+		RequestIDHeader: DefaultRequestIDHeader,
+		RequestIDField:  "request_id",
+		GenRequestID:    genRequestID,
+		StatusLevels: map[int]slog.Level{
+			1: slog.InfoLevel,
+			2: slog.InfoLevel,
+			3: slog.InfoLevel,
+			4: slog.WarnLevel,
+			5: slog.ErrorLevel,
+		},
+		RedactHeaders: []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"},
+	}
+
+	for _, fn := range fns {
+		fn(o)
+	}
+
+	if o.Logger == nil {
+		o.Logger = slog.Std().Logger
+	}
+	return o
+}
+
+// LevelOf get the log level by the response status
+func (o *Options) LevelOf(status int) slog.Level {
+	if lv, ok := o.StatusLevels[status/100]; ok {
+		return lv
+	}
+	return slog.InfoLevel
+}
+
+// New create a middleware with options.
+func New(fns ...func(o *Options)) func(next http.Handler) http.Handler {
+	o := NewOptions(fns...)
+	return func(next http.Handler) http.Handler {
+		return o.Wrap(next)
+	}
+}
+
+// Handler wrap the http.Handler with options. alias of New(fns...)(next)
+func Handler(next http.Handler, fns ...func(o *Options)) http.Handler {
+	return NewOptions(fns...).Wrap(next)
+}
+
+// Wrap the http.Handler, attach a request-scoped SubLogger to the context and log access log at completion.
+func (o *Options) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		reqID := r.Header.Get(o.RequestIDHeader)
+		if !validRequestID(reqID) {
+			reqID = o.GenRequestID()
+		}
+		w.Header().Set(o.RequestIDHeader, reqID)
+
+		ctx := RequestIDKey.WithValue(r.Context(), reqID)
+		ctx = slog.ContextWithTraceHeader(ctx, r.Header)
+		ctx = slog.NewContext(ctx, o.Logger.NewSub().KeepField(o.RequestIDField, reqID))
+		r = r.WithContext(ctx)
+
+		rw := newResponseWriter(w)
+		panicked := true
+		defer func() {
+			if o.Skip != nil && o.Skip(r) {
+				return
+			}
+
+			// the panic will be handled by the outer, eg: Recovery(). respond 500 if not written.
+			if panicked && rw.status == 0 {
+				rw.status = http.StatusInternalServerError
+			}
+			o.logAccess(r, rw, start)
+		}()
+
+		next.ServeHTTP(rw, r)
+		panicked = false
+	})
+}
+
+func (o *Options) logAccess(r *http.Request, rw *responseWriter, start time.Time) {
+	latency := time.Since(start)
+	status := rw.Status()
+	level := o.LevelOf(status)
+	if !o.Logger.IsLevelEnabled(level) {
+		return
+	}
+
+	rec := slog.FromContext(r.Context()).Record()
+	switch o.Format {
+	case FormatCommon, FormatCombined:
+		rec.Log(level, o.formatLine(r, rw, start))
+	default:
+		fields := slog.M{
+			"method":     r.Method,
+			"path":       r.URL.Path,
+			"status":     status,
+			"size":       rw.size,
+			"latency":    latency.String(),
+			"client_ip":  o.clientIP(r),
+			"proto":      r.Proto,
+			"user_agent": r.UserAgent(),
+		}
+		if r.URL.RawQuery != "" {
+			fields["query"] = o.redactQuery(r.URL.RawQuery)
+		}
+		if ref := r.Referer(); ref != "" {
+			fields["referer"] = o.redactURL(ref)
+		}
+		if o.LogHeaders {
+			fields["headers"] = o.redactHeaders(r.Header)
+		}
+		rec.AddFields(fields).Log(level, o.Message)
+	}
+}
+
+// format as Apache common or combined log line. eg:
+//
+//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"
+func (o *Options) formatLine(r *http.Request, rw *responseWriter, t time.Time) string {
+	user := "-"
+	if r.URL.User != nil {
+		if name := r.URL.User.Username(); name != "" {
+			user = name
+		}
+	} else if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = name
+	}
+
+	uri := r.URL.Path
+	if r.URL.RawQuery != "" {
+		uri += "?" + o.redactQuery(r.URL.RawQuery)
+	}
+
+	size := "-"
+	if rw.size > 0 {
+		size = strconv.Itoa(rw.size)
+	}
+
+	var sb strings.Builder
+	sb.Grow(128)
+	sb.WriteString(o.clientIP(r))
+	sb.WriteString(" - ")
+	sb.WriteString(user)
+	sb.WriteString(" [")
+	sb.WriteString(t.Format("02/Jan/2006:15:04:05 -0700"))
+	sb.WriteString(`] "`)
+	sb.WriteString(r.Method)
+	sb.WriteByte(' ')
+	sb.WriteString(uri)
+	sb.WriteByte(' ')
+	sb.WriteString(r.Proto)
+	sb.WriteString(`" `)
+	sb.WriteString(strconv.Itoa(rw.Status()))
+	sb.WriteByte(' ')
+	sb.WriteString(size)
+
+	if o.Format == FormatCombined {
+		sb.WriteString(` "`)
+		sb.WriteString(orDash(o.redactURL(r.Referer())))
+		sb.WriteString(`" "`)
+		sb.WriteString(orDash(r.UserAgent()))
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+func (o *Options) clientIP(r *http.Request) string {
+	if o.TrustProxy {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			if i := strings.IndexByte(xff, ','); i > 0 {
+				xff = xff[:i]
+			}
+			return strings.TrimSpace(xff)
+		}
+		if ip := r.Header.Get("X-Real-Ip"); ip != "" {
+			return ip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func (o *Options) redactHeaders(h http.Header) map[string]string {
+	m := make(map[string]string, len(h))
+	for name, vs := range h {
+		if containsFold(o.RedactHeaders, name) {
+			m[name] = Redacted
+		} else {
+			m[name] = strings.Join(vs, ", ")
+		}
+	}
+	return m
+}
+
+// redact the query values, will keep the order and escaping of others.
+func (o *Options) redactQuery(rawQuery string) string {
+	if len(o.RedactQuery) == 0 {
+		return rawQuery
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		key := pair
+		if j := strings.IndexByte(pair, '='); j >= 0 {
+			key = pair[:j]
+		}
+		if name, err := url.QueryUnescape(key); err == nil && containsFold(o.RedactQuery, name) {
+			pairs[i] = key + "=" + Redacted
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// redact the query values of the URL string. eg: the referer
+func (o *Options) redactURL(s string) string {
+	i := strings.IndexByte(s, '?')
+	if i < 0 || len(o.RedactQuery) == 0 {
+		return s
+	}
+
+	query, fragment := s[i+1:], ""
+	if j := strings.IndexByte(query, '#'); j >= 0 {
+		query, fragment = query[:j], query[j:]
+	}
+	return s[:i+1] + o.redactQuery(query) + fragment
+}
+
+// check the request ID from client, only allow [A-Za-z0-9._-] and limit the length.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > MaxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') && c != '.' && c != '_' && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(ss []string, s string) bool {
+	for _, v := range ss {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func genRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
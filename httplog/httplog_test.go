@@ -0,0 +1,172 @@
+package httplog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+	"github.com/gookit/slog/httplog"
+)
+
+func newTestLogger(buf *bytes.Buffer, f slog.Formatter) *slog.Logger {
+	h := handler.NewSimple(buf, slog.DebugLevel)
+	h.SetFormatter(f)
+	return slog.NewWithHandlers(h)
+}
+
+func TestNew_fields(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, slog.NewJSONFormatter())
+
+	var reqID string
+	mw := httplog.New(func(o *httplog.Options) {
+		o.Logger = l
+		o.LogHeaders = true
+		o.RedactQuery = []string{"token"}
+	})
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID = httplog.RequestID(r)
+		slog.FromContext(r.Context()).Info("in handler")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/users?id=23&token=abc", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("User-Agent", "test-agent")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Eq(t, http.StatusNotFound, w.Code)
+	assert.NotEmpty(t, reqID)
+	assert.Eq(t, reqID, w.Header().Get(httplog.DefaultRequestIDHeader))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	assert.StrContains(t, lines[0], `"request_id":"`+reqID+`"`)
+	assert.StrContains(t, lines[0], `"message":"in handler"`)
+
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal([]byte(lines[1]), &m))
+	assert.Eq(t, "WARNING", m["level"])
+	assert.Eq(t, "http request", m["message"])
+	assert.Eq(t, reqID, m["request_id"])
+	assert.Eq(t, "GET", m["method"])
+	assert.Eq(t, "/users", m["path"])
+	assert.Eq(t, "id=23&token=[REDACTED]", m["query"])
+	assert.Eq(t, float64(404), m["status"])
+	assert.Eq(t, float64(9), m["size"])
+	assert.Eq(t, "192.0.2.1", m["client_ip"])
+	assert.Eq(t, "test-agent", m["user_agent"])
+	assert.NotEmpty(t, m["latency"])
+	headers := m["headers"].(map[string]any)
+	assert.Eq(t, httplog.Redacted, headers["Authorization"])
+	assert.Eq(t, "test-agent", headers["User-Agent"])
+}
+
+func TestHandler_combined(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, slog.NewTextFormatter("{{level}} {{message}}\n"))
+
+	h := httplog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}), func(o *httplog.Options) {
+		o.Logger = l
+		o.Format = httplog.FormatCombined
+		o.TrustProxy = true
+		o.StatusLevels[2] = slog.DebugLevel
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/hello?name=tom", nil)
+	req.Header.Set(httplog.DefaultRequestIDHeader, "req-001")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1, 10.0.0.2")
+	req.Header.Set("Referer", "http://example.com/")
+	req.Header.Set("User-Agent", "test-agent")
+	req.SetBasicAuth("frank", "pwd")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	assert.Eq(t, "req-001", w.Header().Get(httplog.DefaultRequestIDHeader))
+	s := buf.String()
+	assert.True(t, strings.HasPrefix(s, "DEBUG 10.0.0.1 - frank ["), s)
+	assert.StrContains(t, s, `] "GET /hello?name=tom HTTP/1.1" 200 5 "http://example.com/" "test-agent"`)
+	buf.Reset()
+
+	// common format, skip
+	h = httplog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}), func(o *httplog.Options) {
+		o.Logger = l
+		o.Format = httplog.FormatCommon
+		o.Skip = func(r *http.Request) bool { return r.URL.Path == "/health" }
+	})
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/health", nil))
+	assert.Empty(t, buf.String())
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+	s = buf.String()
+	assert.True(t, strings.HasPrefix(s, "ERROR 192.0.2.1 - - ["), s)
+	assert.StrContains(t, s, `] "POST /users HTTP/1.1" 500 -`)
+}
+
+func TestOptions_Wrap_requestID(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, slog.NewTextFormatter("{{request_id}} {{message}}\n"))
+	h := httplog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), func(o *httplog.Options) {
+		o.Logger = l
+	})
+
+	tests := map[string]bool{
+		"req-001.a_B":                          true,
+		"550e8400-e29b-41d4-a716-446655440000": true,
+		"bad\nid":                              false,
+		"bad id":                               false,
+		"<script>":                             false,
+		strings.Repeat("a", httplog.MaxRequestIDLength+1): false,
+	}
+	for id, valid := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(httplog.DefaultRequestIDHeader, id)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+
+		got := w.Header().Get(httplog.DefaultRequestIDHeader)
+		if valid {
+			assert.Eq(t, id, got)
+		} else {
+			assert.NotEq(t, id, got)
+			assert.Len(t, got, 32)
+			assert.NotContains(t, buf.String(), id)
+		}
+	}
+}
+
+func TestOptions_Wrap_panic(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, slog.NewJSONFormatter())
+	h := httplog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler panic")
+	}), func(o *httplog.Options) {
+		o.Logger = l
+		o.RedactQuery = []string{"token"}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("Referer", "http://example.com/login?user=tom&token=abc#top")
+	assert.Panics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	})
+
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Eq(t, "ERROR", m["level"])
+	assert.Eq(t, float64(500), m["status"])
+	assert.Eq(t, "http://example.com/login?user=tom&token=[REDACTED]#top", m["referer"])
+}
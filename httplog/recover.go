@@ -0,0 +1,51 @@
+package httplog
+
+import (
+	"net/http"
+
+	"github.com/gookit/slog"
+)
+
+// Recovery create a middleware that recovers the panic of the next handler, and log it with the stack.
+//
+// If the panic is swallowed, will respond 500 status on the response is not written.
+// The logger default is the std logger.
+//
+// Usage:
+//
+//	h := httplog.Recovery(logger)(mux)
+//	// re-panic after logged
+//	h := httplog.Recovery(logger, slog.WithRePanic)(mux)
+func Recovery(l *slog.Logger, fns ...slog.RecoverFn) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return RecoverHandler(next, l, fns...)
+	}
+}
+
+// RecoverHandler wrap the http.Handler, recover the panic and log it. see Recovery()
+func RecoverHandler(next http.Handler, l *slog.Logger, fns ...slog.RecoverFn) http.Handler {
+	if l == nil {
+		l = slog.Std().Logger
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := newResponseWriter(w)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// the http.ErrAbortHandler is used to abort the handler, should not be logged.
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			l.HandlePanic(v, fns...)
+			if rw.status == 0 {
+				rw.WriteHeader(http.StatusInternalServerError)
+			}
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
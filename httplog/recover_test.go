@@ -0,0 +1,71 @@
+package httplog_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/httplog"
+)
+
+func TestRecovery(t *testing.T) {
+	buf := new(bytes.Buffer)
+	l := newTestLogger(buf, slog.NewTextFormatter("{{level}} {{message}}\n"))
+
+	panicHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("handler error")
+	})
+
+	// swallow the panic, respond 500
+	h := httplog.Recovery(l)(panicHandler)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Eq(t, http.StatusInternalServerError, w.Code)
+	assert.StrContains(t, buf.String(), "ERROR panic recovered: handler error")
+	// the stack starts from the panic site
+	assert.StrContains(t, buf.String(), "\n\tgithub.com/gookit/slog/httplog_test.TestRecovery.func1()\n")
+	buf.Reset()
+
+	// re-panic
+	h = httplog.RecoverHandler(panicHandler, l, slog.WithRePanic)
+	assert.PanicsMsg(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}, "handler error")
+	assert.StrContains(t, buf.String(), "panic recovered: handler error")
+	buf.Reset()
+
+	// abort handler: not logged
+	h = httplog.Recovery(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.Panics(t, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.Empty(t, buf.String())
+
+	// with access log: status is 500
+	h = httplog.New(func(o *httplog.Options) {
+		o.Logger = l
+		o.Format = httplog.FormatCommon
+	})(httplog.Recovery(l)(panicHandler))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	assert.StrContains(t, buf.String(), `"GET /users HTTP/1.1" 500 -`)
+	buf.Reset()
+
+	// flushed before panic: the response has been committed with 200
+	h = httplog.New(func(o *httplog.Options) {
+		o.Logger = l
+		o.Format = httplog.FormatCommon
+	})(httplog.Recovery(l)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		panic("handler error")
+	})))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream", nil))
+	assert.Eq(t, http.StatusOK, w.Code)
+	assert.True(t, w.Flushed)
+	assert.StrContains(t, buf.String(), `"GET /stream HTTP/1.1" 200 -`)
+}
@@ -0,0 +1,67 @@
+package httplog
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// responseWriter wrap the http.ResponseWriter, record the status and size of the response.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func newResponseWriter(w http.ResponseWriter) *responseWriter {
+	return &responseWriter{ResponseWriter: w}
+}
+
+// Status of the response, default is 200 if not written.
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// WriteHeader record the status code
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write record the size of response body
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// Flush implements the http.Flusher. the response is committed with 200 if not written.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, errors.New("httplog: the ResponseWriter is not a http.Hijacker")
+}
+
+// Unwrap the http.ResponseWriter, for the http.ResponseController
+func (w *responseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
@@ -3,6 +3,7 @@ package slog
 import (
 	"context"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gookit/goutil"
@@ -20,15 +21,28 @@ type Logger struct {
 	// mark logger is closed
 	closed bool
 
-	// log handlers for logger
-	handlers   []Handler
+	// log handlers for logger. it is read without lock, see getHandlers(), ReplaceHandlers()
+	handlers   atomic.Pointer[[]Handler]
 	processors []Processor
+	// filters run before the processors. see AddFilter()
+	filters []Filter
 
 	// reusable empty record
 	recordPool sync.Pool
 	// handlers on exit.
 	exitHandlers []func()
 	quitDaemon   chan struct{}
+	// async queue, not nil on enabled async mode. see EnableAsync()
+	async *asyncQueue
+	// consecutive failures count and disabled status of handlers
+	failures map[Handler]int
+	disabled map[Handler]bool
+	// parent logger of the named logger. see Registry
+	parent *Logger
+	// max level for the logger, 0 is not set. see SetMaxLevel()
+	maxLevel atomic.Uint32
+	// states of the rate limited call sites. see Once(), Every(), FirstN()
+	limits sync.Map
 
 	//
 	// logger options
@@ -50,6 +64,20 @@ type Logger struct {
 	CallerFlag CallerFlagMode
 	// BackupArgs backup log input args to Record.Args
 	BackupArgs bool
+	// StackLevel capture the call stack for the records at or above the level. eg: ErrorLevel
+	//
+	// default is 0, not capture. the stack will be output as FieldKeyStacktrace
+	StackLevel Level
+	// StackDepth max frames of the captured stack. default is DefaultStackDepth
+	StackDepth int
+	// StackSkip skip frames after the slog internal frames on capture stack.
+	//
+	// useful for wrap the logger in your functions.
+	StackSkip int
+	// KeyValuesToFields add the key-values of the "w" methods(eg: Infow) to Record.Fields.
+	//
+	// default is false, will add to Record.Data
+	KeyValuesToFields bool
 	// GlobalFields global fields. will be added to all log records
 	//
 	// NOTE: add field need config Formatter template fields.
@@ -59,6 +87,10 @@ type Logger struct {
 	// custom exit, panic handler.
 	ExitFunc  func(code int)
 	PanicFunc func(v any)
+	// ErrorHandler custom handle the errors and panics from handlers.
+	//
+	// If not set, the error will be printed to stderr. see IgnoreErrors, DisableOnErrors and more.
+	ErrorHandler ErrorHandler
 }
 
 // New create a new logger
@@ -107,16 +139,25 @@ func (l *Logger) newRecord() *Record {
 	// reference. Otherwise processors / Record.AddField would mutate the shared
 	// Logger.GlobalFields (leaking per-record/per-request data into all logs and
 	// causing data races). Keep nil when empty to preserve the zero-alloc path.
+	r.Fields = l.mergeGlobalFields(nil)
+	return r
+}
+
+// merge the global fields of parents and the logger to dst. will create dst if it is nil.
+func (l *Logger) mergeGlobalFields(dst M) M {
+	if l.parent != nil {
+		dst = l.parent.mergeGlobalFields(dst)
+	}
+
 	if len(l.GlobalFields) > 0 {
-		fields := make(M, len(l.GlobalFields))
+		if dst == nil {
+			dst = make(M, len(l.GlobalFields))
+		}
 		for k, v := range l.GlobalFields {
-			fields[k] = v
+			dst[k] = v
 		}
-		r.Fields = fields
-	} else {
-		r.Fields = nil
 	}
-	return r
+	return dst
 }
 
 func (l *Logger) releaseRecord(r *Record) {
@@ -124,8 +165,12 @@ func (l *Logger) releaseRecord(r *Record) {
 	r.Time = emptyTime
 	r.Message = ""
 	r.Caller = nil
+	r.callerSet = false
 	r.Fmt = ""
 	r.Args = nil
+	r.typed = r.typed[:0]
+	r.Stack = nil
+	r.limit = nil
 
 	// reuse=true: will not be released
 	if r.reuse || r.freed {
@@ -136,12 +181,13 @@ func (l *Logger) releaseRecord(r *Record) {
 	// so reset it to nil here to avoid keeping a stale map in the pool.
 	r.Ctx = nil
 	r.Extra = nil
-	r.Data = map[string]any{}
+	r.Data = nil
 	r.Fields = nil
 	// reset flags
 	r.inited = false
 	r.reuse = false
 	r.freed = true
+	r.EnableStack = false
 
 	r.CallerSkip = l.CallerSkip
 	l.recordPool.Put(r)
@@ -244,6 +290,7 @@ func (l *Logger) StopDaemon() {
 func (l *Logger) FlushTimeout(timeout time.Duration) {
 	done := make(chan bool, 1)
 	go func() {
+		l.drainAsync()
 		if err := l.lockAndFlushAll(); err != nil {
 			printStderr("slog.FlushTimeout: flush logs error: ", err)
 		}
@@ -262,17 +309,21 @@ func (l *Logger) Sync() error { return Flush() }
 
 // Flush flushes all the logs and attempts to "sync" their data to disk.
 // l.mu is held.
-func (l *Logger) Flush() error { return l.lockAndFlushAll() }
+//
+// In async mode, it will drain the queue before flushing, wait at most AsyncOption.DrainTimeout.
+func (l *Logger) Flush() error {
+	o := l.handlerOwner()
+	o.drainAsync()
+	return o.lockAndFlushAll()
+}
 
 // MustFlush flush logs. will panic on error
-func (l *Logger) MustFlush() {
-	goutil.PanicErr(l.lockAndFlushAll())
-}
+func (l *Logger) MustFlush() { goutil.PanicErr(l.Flush()) }
 
 // FlushAll flushes all the logs and attempts to "sync" their data to disk.
 //
 // alias of the Flush()
-func (l *Logger) FlushAll() error { return l.lockAndFlushAll() }
+func (l *Logger) FlushAll() error { return l.Flush() }
 
 // lockAndFlushAll is like flushAll but locks l.mu first.
 func (l *Logger) lockAndFlushAll() error {
@@ -287,9 +338,13 @@ func (l *Logger) lockAndFlushAll() error {
 func (l *Logger) flushAll() {
 	// flush from fatal down, in case there's trouble flushing.
 	_ = l.VisitAll(func(handler Handler) error {
-		if err := handler.Flush(); err != nil {
-			l.err = err
-			printStderr("slog: call handler.Flush() error:", err)
+		// skip the SugaredLogger self, it will flush all handlers again
+		if sl, ok := handler.(*SugaredLogger); ok && sl.Logger == l {
+			return nil
+		}
+
+		if !l.isDisabled(handler) {
+			l.callHandler(OpFlush, handler, nil)
 		}
 		return nil
 	})
@@ -313,20 +368,39 @@ func (l *Logger) Close() error {
 	l.closed = true
 	l.mu.Unlock()
 
+	l.closeAsync()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
 	_ = l.VisitAll(func(handler Handler) error {
-		if err := handler.Close(); err != nil {
-			l.err = err
-			printStderr("slog: call handler.Close() error:", err)
+		// skip the SugaredLogger self, it will close all handlers again
+		if sl, ok := handler.(*SugaredLogger); ok && sl.Logger == l {
+			return nil
 		}
+		l.callHandler(OpClose, handler, nil)
 		return nil
 	})
 
 	return l.err
 }
 
+// close the async queue and drain the queued records.
+func (l *Logger) closeAsync() {
+	if l.async == nil || l.async.isClosed() {
+		return
+	}
+
+	if !l.async.close() {
+		printStderr("slog: close async queue timeout, remaining records:", l.async.stats().Queued)
+	}
+	if dropped := l.async.stats().Dropped; dropped > 0 {
+		printStderr("slog: async mode has dropped", dropped, "log records")
+	}
+}
+
 // VisitAll logger handlers
 func (l *Logger) VisitAll(fn func(handler Handler) error) error {
-	for _, handler := range l.handlers {
+	for _, handler := range l.getHandlers() {
 		// TIP: you can return nil for ignore error
 		if err := fn(handler); err != nil {
 			return err
@@ -335,18 +409,19 @@ func (l *Logger) VisitAll(fn func(handler Handler) error) error {
 	return nil
 }
 
-// Reset the logger. will reset: handlers, processors, closed=false
+// Reset the logger. will reset: handlers, processors, filters, closed=false
 func (l *Logger) Reset() {
 	l.closed = false
 	l.ResetHandlers()
 	l.ResetProcessors()
+	l.ResetFilters()
 }
 
 // ResetProcessors for the logger
 func (l *Logger) ResetProcessors() { l.processors = make([]Processor, 0) }
 
 // ResetHandlers for the logger
-func (l *Logger) ResetHandlers() { l.handlers = make([]Handler, 0) }
+func (l *Logger) ResetHandlers() { l.SetHandlers(make([]Handler, 0)) }
 
 // Exit logger handle
 func (l *Logger) Exit(code int) {
@@ -379,7 +454,15 @@ func (l *Logger) DoNothingOnPanicFatal() {
 }
 
 // HandlersNum returns the number of handlers
-func (l *Logger) HandlersNum() int { return len(l.handlers) }
+func (l *Logger) HandlersNum() int { return len(l.getHandlers()) }
+
+// get the current handlers snapshot, it is safe to read without lock.
+func (l *Logger) getHandlers() []Handler {
+	if hs := l.handlers.Load(); hs != nil {
+		return *hs
+	}
+	return nil
+}
 
 // LastErr get, will clear it after read.
 func (l *Logger) LastErr() error {
@@ -409,12 +492,56 @@ func (l *Logger) PushHandler(h Handler) { l.PushHandlers(h) }
 // PushHandlers to the logger
 func (l *Logger) PushHandlers(hs ...Handler) {
 	if len(hs) > 0 {
-		l.handlers = append(l.handlers, hs...)
+		old := l.getHandlers()
+		// copy on write, the old snapshot may be read on logging
+		nhs := make([]Handler, 0, len(old)+len(hs))
+		nhs = append(append(nhs, old...), hs...)
+		l.handlers.Store(&nhs)
 	}
 }
 
 // SetHandlers for the logger
-func (l *Logger) SetHandlers(hs []Handler) { l.handlers = hs }
+func (l *Logger) SetHandlers(hs []Handler) { l.handlers.Store(&hs) }
+
+// ReplaceHandlers atomically replace the handlers of the logger, returns the old handlers.
+// It is safe to call it on logging, the queued records in async mode are handled by the old handlers.
+//
+// NOTE: the old handlers will not be flushed and closed, the caller should do it.
+func (l *Logger) ReplaceHandlers(hs []Handler) []Handler {
+	return l.replaceHandlers(hs, false, 0)
+}
+
+// ReplaceHandlersLevel like ReplaceHandlers, and set the max level at once. the maxLevel=0 will reset it.
+//
+// The records handled by the new handlers are always checked by the new max level.
+func (l *Logger) ReplaceHandlersLevel(hs []Handler, maxLevel Level) []Handler {
+	return l.replaceHandlers(hs, true, maxLevel)
+}
+
+func (l *Logger) replaceHandlers(hs []Handler, setLevel bool, maxLevel Level) []Handler {
+	l.drainAsync()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// set the level before swap handlers, the level will be checked again under l.mu. see writeRecord()
+	if setLevel {
+		l.maxLevel.Store(uint32(maxLevel))
+	}
+
+	var old []Handler
+	if ptr := l.handlers.Swap(&hs); ptr != nil {
+		old = *ptr
+	}
+
+	// clear the failure states of the old handlers
+	if len(l.failures) > 0 || len(l.disabled) > 0 {
+		for _, h := range old {
+			delete(l.failures, h)
+			delete(l.disabled, h)
+		}
+	}
+	return old
+}
 
 // AddProcessor to the logger
 func (l *Logger) AddProcessor(p Processor) { l.processors = append(l.processors, p) }
@@ -428,6 +555,21 @@ func (l *Logger) AddProcessors(ps ...Processor) { l.processors = append(l.proces
 // SetProcessors for the logger
 func (l *Logger) SetProcessors(ps []Processor) { l.processors = ps }
 
+// AddFilter to the logger. the filters run before the processors, the record
+// will be dropped if any filter not allow it.
+//
+// NOTE: the Panic and Fatal level records are never dropped.
+func (l *Logger) AddFilter(f Filter) { l.filters = append(l.filters, f) }
+
+// AddFilters to the logger. see AddFilter()
+func (l *Logger) AddFilters(fs ...Filter) { l.filters = append(l.filters, fs...) }
+
+// SetFilters for the logger
+func (l *Logger) SetFilters(fs []Filter) { l.filters = fs }
+
+// ResetFilters for the logger
+func (l *Logger) ResetFilters() { l.filters = make([]Filter, 0) }
+
 // -------------------------- New sub-logger -----------------------------
 
 // NewSub return a new sub logger on the logger, can keep fields/data/ctx for sub logger.
@@ -532,7 +674,10 @@ func (l *Logger) shouldHandle(level Level) bool {
 	if level <= FatalLevel { // panic & fatal: keep their panic/exit side effects
 		return true
 	}
-	for _, h := range l.handlers {
+	if !l.IsLevelEnabled(level) {
+		return false
+	}
+	for _, h := range l.handlerOwner().getHandlers() {
 		if h.IsHandling(level) {
 			return true
 		}
@@ -581,12 +726,51 @@ func (l *Logger) logfCtx(ctx context.Context, level Level, format string, args [
 	r.logf(level, format, args)
 }
 
+// logErr the error with level, the err will be added to Fields as FieldKeyError
+func (l *Logger) logErr(level Level, err error) {
+	if !l.shouldHandle(level) {
+		return
+	}
+	r := l.newRecord()
+	r.AddField(FieldKeyError, err)
+	r.CallerSkip++
+	r.log(level, []any{err})
+}
+
+// logStack a message with level and the call stack
+func (l *Logger) logStack(level Level, args []any) {
+	if !l.shouldHandle(level) {
+		return
+	}
+	r := l.newRecord()
+	r.EnableStack = true
+	r.CallerSkip++
+	r.log(level, args)
+}
+
+// logw a message with level and key-values
+func (l *Logger) logw(level Level, msg string, kvs []any) {
+	if !l.shouldHandle(level) {
+		return
+	}
+	r := l.newRecord()
+	r.CallerSkip++
+	r.logw(level, msg, kvs)
+}
+
 // Log a message with level
 func (l *Logger) Log(level Level, args ...any) { l.log(level, args) }
 
 // Logf a format message with level
 func (l *Logger) Logf(level Level, format string, args ...any) { l.logf(level, format, args) }
 
+// Logw a message with level and key-values. see KeyValuesToFields
+//
+// Usage:
+//
+//	l.Logw(slog.InfoLevel, "user login", "user", "inhere", "cost", cost)
+func (l *Logger) Logw(level Level, msg string, kvs ...any) { l.logw(level, msg, kvs) }
+
 // Print logs a message at level PrintLevel
 func (l *Logger) Print(args ...any) { l.log(PrintLevel, args) }
 
@@ -602,6 +786,9 @@ func (l *Logger) Trace(args ...any) { l.log(TraceLevel, args) }
 // Tracef logs a message at level trace
 func (l *Logger) Tracef(format string, args ...any) { l.logf(TraceLevel, format, args) }
 
+// Tracew logs a message at level trace with key-values
+func (l *Logger) Tracew(msg string, kvs ...any) { l.logw(TraceLevel, msg, kvs) }
+
 // TraceCtx logs a message at level trace with context
 func (l *Logger) TraceCtx(ctx context.Context, args ...any) { l.logCtx(ctx, TraceLevel, args) }
 
@@ -616,6 +803,9 @@ func (l *Logger) Debug(args ...any) { l.log(DebugLevel, args) }
 // Debugf logs a message at level debug
 func (l *Logger) Debugf(format string, args ...any) { l.logf(DebugLevel, format, args) }
 
+// Debugw logs a message at level debug with key-values
+func (l *Logger) Debugw(msg string, kvs ...any) { l.logw(DebugLevel, msg, kvs) }
+
 // DebugCtx logs a message at level debug with context
 func (l *Logger) DebugCtx(ctx context.Context, args ...any) { l.logCtx(ctx, DebugLevel, args) }
 
@@ -630,6 +820,9 @@ func (l *Logger) Info(args ...any) { l.log(InfoLevel, args) }
 // Infof logs a message at level Info
 func (l *Logger) Infof(format string, args ...any) { l.logf(InfoLevel, format, args) }
 
+// Infow logs a message at level Info with key-values
+func (l *Logger) Infow(msg string, kvs ...any) { l.logw(InfoLevel, msg, kvs) }
+
 // InfoCtx logs a message at level Info with context
 func (l *Logger) InfoCtx(ctx context.Context, args ...any) { l.logCtx(ctx, InfoLevel, args) }
 
@@ -644,6 +837,9 @@ func (l *Logger) Notice(args ...any) { l.log(NoticeLevel, args) }
 // Noticef logs a message at level notice
 func (l *Logger) Noticef(format string, args ...any) { l.logf(NoticeLevel, format, args) }
 
+// Noticew logs a message at level notice with key-values
+func (l *Logger) Noticew(msg string, kvs ...any) { l.logw(NoticeLevel, msg, kvs) }
+
 // NoticeCtx logs a message at level notice with context
 func (l *Logger) NoticeCtx(ctx context.Context, args ...any) { l.logCtx(ctx, NoticeLevel, args) }
 
@@ -658,6 +854,9 @@ func (l *Logger) Warn(args ...any) { l.log(WarnLevel, args) }
 // Warnf logs a message at level Warn
 func (l *Logger) Warnf(format string, args ...any) { l.logf(WarnLevel, format, args) }
 
+// Warnw logs a message at level Warn with key-values
+func (l *Logger) Warnw(msg string, kvs ...any) { l.logw(WarnLevel, msg, kvs) }
+
 // WarnCtx logs a message at level Warn with context
 func (l *Logger) WarnCtx(ctx context.Context, args ...any) { l.logCtx(ctx, WarnLevel, args) }
 
@@ -675,10 +874,15 @@ func (l *Logger) Error(args ...any) { l.log(ErrorLevel, args) }
 // Errorf logs a message at level error
 func (l *Logger) Errorf(format string, args ...any) { l.logf(ErrorLevel, format, args) }
 
+// Errorw logs a message at level error with key-values
+func (l *Logger) Errorw(msg string, kvs ...any) { l.logw(ErrorLevel, msg, kvs) }
+
 // ErrorT logs an error type at level error
+//
+// The err is added to Fields as FieldKeyError, will be rendered with the unwrapped chain. see ErrorChain()
 func (l *Logger) ErrorT(err error) {
 	if err != nil {
-		l.log(ErrorLevel, []any{err})
+		l.logErr(ErrorLevel, err)
 	}
 }
 
@@ -690,8 +894,8 @@ func (l *Logger) ErrorfCtx(ctx context.Context, format string, args ...any) {
 	l.logfCtx(ctx, ErrorLevel, format, args)
 }
 
-// Stack logs a error message and with call stack. TODO
-// func EStack(args ...any) { std.log(ErrorLevel, args) }
+// EStack logs an error message and with the call stack
+func (l *Logger) EStack(args ...any) { l.logStack(ErrorLevel, args) }
 
 // Fatal logs a message at level fatal
 func (l *Logger) Fatal(args ...any) { l.log(FatalLevel, args) }
@@ -699,6 +903,9 @@ func (l *Logger) Fatal(args ...any) { l.log(FatalLevel, args) }
 // Fatalf logs a message at level fatal
 func (l *Logger) Fatalf(format string, args ...any) { l.logf(FatalLevel, format, args) }
 
+// Fatalw logs a message at level fatal with key-values
+func (l *Logger) Fatalw(msg string, kvs ...any) { l.logw(FatalLevel, msg, kvs) }
+
 // Fatalln logs a message at level fatal
 func (l *Logger) Fatalln(args ...any) { l.log(FatalLevel, args) }
 
@@ -716,6 +923,9 @@ func (l *Logger) Panic(args ...any) { l.log(PanicLevel, args) }
 // Panicf logs a message at level panic
 func (l *Logger) Panicf(format string, args ...any) { l.logf(PanicLevel, format, args) }
 
+// Panicw logs a message at level panic with key-values
+func (l *Logger) Panicw(msg string, kvs ...any) { l.logw(PanicLevel, msg, kvs) }
+
 // Panicln logs a message at level panic
 func (l *Logger) Panicln(args ...any) { l.log(PanicLevel, args) }
 
@@ -0,0 +1,379 @@
+package slog
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OverflowPolicy define the behavior on the async queue is full.
+type OverflowPolicy uint8
+
+// String get policy name
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowDropNewest:
+		return "drop_newest"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	case OverflowDropBelowLevel:
+		return "drop_below_level"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// OverflowBlock block the caller until the queue has free space. it's default policy.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropNewest drop the new record when the queue is full.
+	OverflowDropNewest
+	// OverflowDropOldest drop the oldest record in the queue, then push the new record.
+	OverflowDropOldest
+	// OverflowDropBelowLevel drop the new record if it is less severe than AsyncOption.DropLevel,
+	// otherwise block the caller like OverflowBlock.
+	OverflowDropBelowLevel
+)
+
+const (
+	// DefaultAsyncQueueSize default queue size for the async mode
+	DefaultAsyncQueueSize = 1024
+	// DefaultDrainTimeout default max wait time for drain the async queue
+	DefaultDrainTimeout = 5 * time.Second
+)
+
+// AsyncOption for the async dispatch mode of the Logger
+type AsyncOption struct {
+	// QueueSize max number of records in the queue. default is DefaultAsyncQueueSize
+	QueueSize int
+	// Workers number of background workers. default is 1
+	//
+	// NOTE: the records order is not guaranteed if Workers > 1
+	Workers int
+	// Overflow policy on the queue is full. default is OverflowBlock
+	Overflow OverflowPolicy
+	// DropLevel valid on Overflow=OverflowDropBelowLevel.
+	//
+	// eg: DropLevel=WarnLevel, on the queue is full: info, debug... will be dropped, warn, error will wait.
+	DropLevel Level
+	// DrainTimeout max wait time for drain the queue on Flush() and Close(). default is DefaultDrainTimeout
+	DrainTimeout time.Duration
+}
+
+// AsyncOptionFn func for config the AsyncOption
+type AsyncOptionFn func(opt *AsyncOption)
+
+// AsyncStats statistics of the async mode
+type AsyncStats struct {
+	// Queued number of records waiting in the queue
+	Queued int
+	// Handled number of records delivered by workers
+	Handled uint64
+	// Dropped number of records dropped by the overflow policy
+	Dropped uint64
+}
+
+// EnableAsync enable the async dispatch mode for the logger.
+//
+// In async mode, records are put into a bounded queue and delivered to handlers by background workers,
+// so a slow handler will not block the logging goroutines.
+//
+// NOTE:
+//   - must be called before logging, and please call Close() before app exit.
+//   - panic and fatal records are still handled synchronously, after the queue is drained.
+//
+// Usage:
+//
+//	l := slog.NewWithHandlers(h1, h2)
+//	l.EnableAsync(func(opt *slog.AsyncOption) {
+//		opt.QueueSize = 4096
+//		opt.Overflow = slog.OverflowDropOldest
+//	})
+//	defer l.Close()
+func (l *Logger) EnableAsync(fns ...AsyncOptionFn) *Logger {
+	if l.async != nil {
+		return l
+	}
+
+	opt := &AsyncOption{
+		QueueSize:    DefaultAsyncQueueSize,
+		Workers:      1,
+		DrainTimeout: DefaultDrainTimeout,
+	}
+	for _, fn := range fns {
+		fn(opt)
+	}
+
+	if opt.QueueSize <= 0 {
+		opt.QueueSize = DefaultAsyncQueueSize
+	}
+	if opt.Workers <= 0 {
+		opt.Workers = 1
+	}
+	if opt.DrainTimeout <= 0 {
+		opt.DrainTimeout = DefaultDrainTimeout
+	}
+
+	l.async = newAsyncQueue(opt)
+	l.async.start(l)
+	return l
+}
+
+// IsAsync check the logger is in async mode
+func (l *Logger) IsAsync() bool { return l.async != nil }
+
+// AsyncStats get statistics of the async mode. return empty stats if not in async mode.
+func (l *Logger) AsyncStats() AsyncStats {
+	if l.async == nil {
+		return AsyncStats{}
+	}
+	return l.async.stats()
+}
+
+// push record to the async queue. return false if the queue has been closed.
+func (l *Logger) asyncWrite(r *Record) bool {
+	// init the record on current goroutine: time, level name, caller
+	// TIP: r.logger maybe is a named child logger of l.
+	rl := r.logger
+	r.Init(rl.LowerLevelName)
+	if rl.ReportCaller && !r.callerSet {
+		if caller, ok := getCaller(r.CallerSkip); ok {
+			r.Caller = &caller
+		}
+	}
+
+	// the record dropped by the filters is not queued
+	if r.Level > FatalLevel && !rl.allowRecord(r) {
+		return true
+	}
+	r.captureStack(rl)
+
+	return l.async.push(r.Clone())
+}
+
+// handle the queued record on worker goroutine.
+func (l *Logger) asyncHandle(r *Record) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// the max level may be changed with the handlers. see ReplaceHandlersLevel()
+	if r.Level > FatalLevel && !r.logger.IsLevelEnabled(r.Level) {
+		return
+	}
+
+	processed := false
+	for _, handler := range l.getHandlers() {
+		if handler.IsHandling(r.Level) && !l.isDisabled(handler) {
+			if !processed {
+				processed = true
+				r.logger.runProcessors(r)
+			}
+			l.handleRecord(handler, r)
+		}
+	}
+
+	if r.Level <= ErrorLevel {
+		l.flushAll()
+	}
+}
+
+// drain the async queue, wait all queued records are handled.
+func (l *Logger) drainAsync() {
+	if l.async != nil {
+		if !l.async.waitIdle() {
+			printStderr("slog: drain async queue timeout, remaining records:", l.async.stats().Queued)
+		}
+	}
+}
+
+// asyncQueue bounded ring buffer queue for records.
+type asyncQueue struct {
+	opt *AsyncOption
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  []*Record
+	head int
+	size int
+	// number of records being handled by workers
+	busy   int
+	closed bool
+
+	wg      sync.WaitGroup
+	handled atomic.Uint64
+	dropped atomic.Uint64
+}
+
+func newAsyncQueue(opt *AsyncOption) *asyncQueue {
+	q := &asyncQueue{
+		opt: opt,
+		buf: make([]*Record, opt.QueueSize),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *asyncQueue) start(l *Logger) {
+	q.wg.Add(q.opt.Workers)
+	for i := 0; i < q.opt.Workers; i++ {
+		go q.work(l)
+	}
+}
+
+func (q *asyncQueue) work(l *Logger) {
+	defer q.wg.Done()
+
+	for {
+		r, ok := q.pop()
+		if !ok {
+			return
+		}
+
+		l.asyncHandle(r)
+		q.handled.Add(1)
+		q.done()
+	}
+}
+
+// push record to the queue, will apply the overflow policy on the queue is full.
+// return false if the queue has been closed.
+func (q *asyncQueue) push(r *Record) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.size == len(q.buf) && !q.closed {
+		switch q.opt.Overflow {
+		case OverflowDropNewest:
+			q.dropped.Add(1)
+			return true
+		case OverflowDropOldest:
+			q.buf[q.head] = nil
+			q.head = (q.head + 1) % len(q.buf)
+			q.size--
+			q.dropped.Add(1)
+		case OverflowDropBelowLevel:
+			if r.Level > q.opt.DropLevel {
+				q.dropped.Add(1)
+				return true
+			}
+			q.cond.Wait()
+		default: // OverflowBlock
+			q.cond.Wait()
+		}
+	}
+
+	if q.closed {
+		return false
+	}
+
+	q.buf[(q.head+q.size)%len(q.buf)] = r
+	q.size++
+	q.cond.Broadcast()
+	return true
+}
+
+// pop a record from the queue, will block until there is a record or the queue is closed.
+func (q *asyncQueue) pop() (*Record, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for q.size == 0 {
+		if q.closed {
+			return nil, false
+		}
+		q.cond.Wait()
+	}
+
+	r := q.buf[q.head]
+	q.buf[q.head] = nil
+	q.head = (q.head + 1) % len(q.buf)
+	q.size--
+	q.busy++
+	q.cond.Broadcast()
+	return r, true
+}
+
+// mark a record is handled
+func (q *asyncQueue) done() {
+	q.mu.Lock()
+	q.busy--
+	q.cond.Broadcast()
+	q.mu.Unlock()
+}
+
+// waitIdle wait until the queue is empty and no record is being handled.
+// return false on wait timeout.
+func (q *asyncQueue) waitIdle() bool {
+	idle := make(chan struct{})
+	// stop waiting on timeout, guarded by q.mu
+	var stop bool
+	go func() {
+		q.mu.Lock()
+		for (q.size > 0 || q.busy > 0) && !stop {
+			q.cond.Wait()
+		}
+		q.mu.Unlock()
+		close(idle)
+	}()
+
+	timer := time.NewTimer(q.opt.DrainTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-idle:
+		return true
+	case <-timer.C:
+		// wake up the waiting goroutine to exit
+		q.mu.Lock()
+		stop = true
+		q.cond.Broadcast()
+		q.mu.Unlock()
+		<-idle
+		return false
+	}
+}
+
+// close the queue and wait workers to exit. the queued records will be drained before exit.
+// return false on wait timeout.
+func (q *asyncQueue) close() bool {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return true
+	}
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	exited := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(exited)
+	}()
+
+	select {
+	case <-exited:
+		return true
+	case <-time.After(q.opt.DrainTimeout):
+		return false
+	}
+}
+
+func (q *asyncQueue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
+
+func (q *asyncQueue) stats() AsyncStats {
+	q.mu.Lock()
+	queued := q.size
+	q.mu.Unlock()
+
+	return AsyncStats{
+		Queued:  queued,
+		Handled: q.handled.Load(),
+		Dropped: q.dropped.Load(),
+	}
+}
@@ -0,0 +1,27 @@
+package slog
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestAsyncQueue_waitIdle_timeout(t *testing.T) {
+	q := newAsyncQueue(&AsyncOption{QueueSize: 2, DrainTimeout: 10 * time.Millisecond})
+	// a record is being handled
+	q.busy = 1
+
+	// the waiting goroutines exit on timeout
+	before := runtime.NumGoroutine()
+	for i := 0; i < 5; i++ {
+		assert.False(t, q.waitIdle())
+	}
+	assert.Eq(t, before, runtime.NumGoroutine())
+
+	q.mu.Lock()
+	q.busy = 0
+	q.mu.Unlock()
+	assert.True(t, q.waitIdle())
+}
@@ -0,0 +1,188 @@
+package slog_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+// blockHandler will block Handle() until the gate is opened.
+type blockHandler struct {
+	testHandler
+	mu   sync.Mutex
+	gate chan struct{}
+	msgs []string
+}
+
+func newBlockHandler() *blockHandler {
+	return &blockHandler{gate: make(chan struct{})}
+}
+
+func (h *blockHandler) Handle(r *slog.Record) error {
+	<-h.gate
+	h.mu.Lock()
+	h.msgs = append(h.msgs, r.Message)
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *blockHandler) Messages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.msgs...)
+}
+
+func TestLogger_EnableAsync(t *testing.T) {
+	h := newTestHandler()
+	l := slog.NewWithHandlers(h)
+	l.EnableAsync()
+	assert.True(t, l.IsAsync())
+
+	for i := 0; i < 10; i++ {
+		l.Info("async message", i)
+	}
+	assert.NoErr(t, l.Flush())
+
+	s := h.String()
+	assert.StrContains(t, s, "async message 0")
+	assert.StrContains(t, s, "async message 9")
+	assert.StrContains(t, s, "logger_async_test.go")
+	assert.Eq(t, uint64(10), l.AsyncStats().Handled)
+	assert.Eq(t, 0, l.AsyncStats().Queued)
+
+	assert.NoErr(t, l.Close())
+	// write on closed: fallback to sync mode
+	l.Info("after closed")
+	assert.StrContains(t, h.String(), "after closed")
+}
+
+func TestLogger_EnableAsync_notBlockCaller(t *testing.T) {
+	h := newBlockHandler()
+	l := slog.NewWithHandlers(h)
+	l.EnableAsync(func(opt *slog.AsyncOption) {
+		opt.QueueSize = 8
+	})
+
+	done := make(chan struct{})
+	go func() {
+		l.Info("message1")
+		l.Info("message2")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("logging is blocked by the slow handler")
+	}
+
+	close(h.gate)
+	assert.NoErr(t, l.Close())
+	assert.Eq(t, []string{"message1", "message2"}, h.Messages())
+}
+
+func TestLogger_EnableAsync_overflow(t *testing.T) {
+	t.Run("drop newest", func(t *testing.T) {
+		h := newBlockHandler()
+		l := slog.NewWithHandlers(h)
+		l.EnableAsync(func(opt *slog.AsyncOption) {
+			opt.QueueSize = 2
+			opt.Overflow = slog.OverflowDropNewest
+		})
+
+		l.Info("message0")
+		// wait worker take the first record
+		time.Sleep(20 * time.Millisecond)
+		for i := 1; i <= 5; i++ {
+			l.Info("message" + string(rune('0'+i)))
+		}
+		assert.Eq(t, uint64(3), l.AsyncStats().Dropped)
+
+		close(h.gate)
+		assert.NoErr(t, l.Close())
+		assert.Eq(t, []string{"message0", "message1", "message2"}, h.Messages())
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		h := newBlockHandler()
+		l := slog.NewWithHandlers(h)
+		l.EnableAsync(func(opt *slog.AsyncOption) {
+			opt.QueueSize = 2
+			opt.Overflow = slog.OverflowDropOldest
+		})
+
+		l.Info("message0")
+		time.Sleep(20 * time.Millisecond)
+		for i := 1; i <= 5; i++ {
+			l.Info("message" + string(rune('0'+i)))
+		}
+		assert.Eq(t, uint64(3), l.AsyncStats().Dropped)
+
+		close(h.gate)
+		assert.NoErr(t, l.Close())
+		assert.Eq(t, []string{"message0", "message4", "message5"}, h.Messages())
+	})
+
+	t.Run("drop below level", func(t *testing.T) {
+		h := newBlockHandler()
+		l := slog.NewWithHandlers(h)
+		l.EnableAsync(func(opt *slog.AsyncOption) {
+			opt.QueueSize = 1
+			opt.Overflow = slog.OverflowDropBelowLevel
+			opt.DropLevel = slog.WarnLevel
+		})
+
+		l.Info("message0")
+		time.Sleep(20 * time.Millisecond)
+		l.Info("message1")
+		l.Info("message2") // dropped
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			close(h.gate)
+		}()
+		l.Warn("message3") // wait for free space
+		assert.NoErr(t, l.Close())
+		assert.Eq(t, uint64(1), l.AsyncStats().Dropped)
+		assert.Eq(t, []string{"message0", "message1", "message3"}, h.Messages())
+	})
+}
+
+func TestLogger_EnableAsync_panicFatal(t *testing.T) {
+	h := newTestHandler()
+	l := slog.NewWithHandlers(h)
+	l.EnableAsync()
+
+	var out string
+	l.PanicFunc = func(v any) { out = h.String() }
+	l.ExitFunc = func(code int) { out = h.String() }
+
+	l.Info("info message")
+	l.Panic("panic message")
+	assert.StrContains(t, out, "info message")
+	assert.StrContains(t, out, "panic message")
+
+	h.Reset()
+	l.Warn("warn message")
+	l.Fatal("fatal message")
+	assert.True(t, strings.Index(out, "warn message") < strings.Index(out, "fatal message"))
+	assert.StrContains(t, out, "fatal message")
+	assert.NoErr(t, l.Close())
+}
+
+func TestSugaredLogger_EnableAsync(t *testing.T) {
+	buf := newBuffer()
+	sl := slog.NewSugared(buf, slog.InfoLevel)
+	sl.EnableAsync()
+
+	sl.Info("sugared async message")
+	sl.Error("sugared async error")
+	assert.NoErr(t, sl.Flush())
+	assert.StrContains(t, buf.String(), "sugared async message")
+	assert.StrContains(t, buf.String(), "sugared async error")
+	assert.NoErr(t, sl.Close())
+}
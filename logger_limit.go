@@ -0,0 +1,143 @@
+package slog
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// the kinds of rate limit
+const (
+	limitOnce uint8 = iota
+	limitEvery
+	limitFirstN
+)
+
+// the key of a rate limited call site. pc is used on the name is empty.
+type limitKey struct {
+	kind uint8
+	pc   uintptr
+	name string
+}
+
+// the state of a rate limited call site
+type limitState struct {
+	mu    sync.Mutex
+	count uint64
+	last  time.Time
+	// suppressed count since the last passed
+	suppressed uint64
+}
+
+// Once return a record that writes log only at the first call of the call site.
+//
+// The key default is the caller PC, can be customized by the key argument.
+// The limit is consumed on writing, the record of disabled level will not consume it.
+//
+// NOTE: the state of each key is kept for the logger lifetime, so don't use the
+// unbounded dynamic keys. eg: the user ID, request ID
+//
+// Usage:
+//
+//	for _, item := range items {
+//		logger.Once().Warn("the item.Name is deprecated")
+//	}
+func (l *Logger) Once(key ...string) *Record { return l.limitRecord(limitOnce, 0, key) }
+
+// Every return a record that writes log at most once every the duration of the call site.
+//
+// The suppressed count will be added to the next written record as the field FieldKeySuppressed.
+//
+// Usage:
+//
+//	logger.Every(time.Minute).Warnf("queue is full, size: %d", size)
+func (l *Logger) Every(d time.Duration, key ...string) *Record {
+	return l.limitRecord(limitEvery, int64(d), key)
+}
+
+// FirstN return a record that writes log only at the first n calls of the call site.
+//
+// Usage:
+//
+//	logger.FirstN(3).Info("retry to connect server")
+func (l *Logger) FirstN(n int, key ...string) *Record {
+	return l.limitRecord(limitFirstN, int64(n), key)
+}
+
+// limitRecord create a record by the rate limit, must be called by Once, Every, FirstN.
+//
+// The limit is checked on writing the record. see Record.allowLimit()
+func (l *Logger) limitRecord(kind uint8, arg int64, key []string) *Record {
+	lk := limitKey{kind: kind}
+	if len(key) > 0 && key[0] != "" {
+		lk.name = key[0]
+	} else {
+		// skip: limitRecord, Once/Every/FirstN
+		lk.pc, _, _, _ = runtime.Caller(2)
+	}
+
+	v, ok := l.limits.Load(lk)
+	if !ok {
+		v, _ = l.limits.LoadOrStore(lk, &limitState{})
+	}
+
+	r := l.newRecord()
+	r.limit = &recordLimit{st: v.(*limitState), kind: kind, arg: arg}
+	return r
+}
+
+// the pending rate limit of a record
+type recordLimit struct {
+	st   *limitState
+	kind uint8
+	arg  int64
+}
+
+// allowLimit consume the rate limit of the record. returns false if the record is suppressed.
+//
+// NOTE: the Panic, Fatal level records are counted, but never suppressed.
+func (r *Record) allowLimit(level Level) bool {
+	lm := r.limit
+	if lm == nil {
+		return true
+	}
+	r.limit = nil
+
+	st := lm.st
+	st.mu.Lock()
+	passed := st.allow(lm.kind, lm.arg, r.logger.TimeClock)
+	var suppressed uint64
+	if passed {
+		suppressed, st.suppressed = st.suppressed, 0
+	} else {
+		st.suppressed++
+	}
+	st.mu.Unlock()
+
+	if passed && suppressed > 0 {
+		if r.Fields == nil {
+			r.Fields = make(M, 1)
+		}
+		r.Fields[FieldKeySuppressed] = suppressed
+	}
+	return passed || level <= FatalLevel
+}
+
+// check the call is allowed and count it, must be called in lock.
+func (st *limitState) allow(kind uint8, arg int64, clock ClockFn) bool {
+	st.count++
+	switch kind {
+	case limitOnce:
+		return st.count == 1
+	case limitFirstN:
+		return arg > 0 && st.count <= uint64(arg)
+	}
+
+	// limitEvery
+	now := clock.Now()
+	if st.count > 1 && now.Sub(st.last) < time.Duration(arg) {
+		return false
+	}
+	st.last = now
+	return true
+}
@@ -0,0 +1,124 @@
+package slog_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func TestLogger_Once(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l := slog.NewWithHandlers(h)
+
+	for i := 0; i < 3; i++ {
+		l.Once().Warn("once message")
+		l.Once("custom-key").Info("once with key")
+		l.Once("custom-key").Info("once with key")
+	}
+	assert.Eq(t, "WARNING once message\nINFO once with key\n", h.ResetGet())
+
+	// other call site
+	l.Once().Warn("once message")
+	assert.Eq(t, "WARNING once message\n", h.ResetGet())
+
+	// builder
+	for i := 0; i < 3; i++ {
+		l.Once().Build(slog.InfoLevel).Int("i", i).Msg("build message")
+	}
+	assert.Eq(t, "INFO build message i=0\n", h.ResetGet())
+
+	// the disabled level does not consume the limit
+	l.SetMaxLevel(slog.InfoLevel)
+	l.Once("level-key").Debug("debug message")
+	l.Once("level-key").Build(slog.DebugLevel).Msg("debug message")
+	l.Once("level-key").Error("error message")
+	l.Once("level-key").Error("error message")
+	assert.Eq(t, "ERROR error message\n", h.ResetGet())
+}
+
+func TestLogger_FirstN(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+
+	for i := 0; i < 5; i++ {
+		l.FirstN(2).Infof("message %d", i)
+	}
+	assert.Eq(t, "INFO message 0\nINFO message 1\n", h.ResetGet())
+
+	// never suppress panic, fatal
+	for i := 0; i < 3; i++ {
+		l.FirstN(1).Panic("panic message")
+	}
+	assert.Eq(t, 3, strings.Count(h.ResetGet(), "panic message"))
+
+	// package func
+	slog.Reset()
+	slog.AddHandler(h)
+	for i := 0; i < 3; i++ {
+		slog.FirstN(1).Info("std message")
+	}
+	assert.Eq(t, 1, strings.Count(h.ResetGet(), "std message"))
+}
+
+func TestLogger_Every(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+
+	now := time.Now()
+	l.TimeClock = func() time.Time { return now }
+
+	logEvery := func() {
+		l.Every(time.Second).Warn("every message")
+	}
+
+	for i := 0; i < 5; i++ {
+		logEvery()
+		now = now.Add(300 * time.Millisecond)
+	}
+	// at: 0, 1.2s
+	s := h.ResetGet()
+	assert.Eq(t, 2, strings.Count(s, "every message"))
+	assert.StrContains(t, s, `"suppressed":3`)
+
+	// not suppressed
+	now = now.Add(time.Second)
+	logEvery()
+	assert.NotContains(t, h.ResetGet(), slog.FieldKeySuppressed)
+}
+
+func TestLogger_limit_withChain(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l := slog.NewWithHandlers(h)
+
+	chains := map[string]func(r *slog.Record, i int) *slog.Record{
+		"WithField":   func(r *slog.Record, i int) *slog.Record { return r.WithField("i", i) },
+		"WithFields":  func(r *slog.Record, i int) *slog.Record { return r.WithFields(slog.M{"i": i}) },
+		"WithData":    func(r *slog.Record, i int) *slog.Record { return r.WithData(slog.M{"i": i}) },
+		"WithContext": func(r *slog.Record, i int) *slog.Record { return r.WithContext(context.Background()) },
+		"WithError":   func(r *slog.Record, i int) *slog.Record { return r.WithError(errors.New("an error")) },
+		"WithTime":    func(r *slog.Record, i int) *slog.Record { return r.WithTime(time.Now()) },
+	}
+
+	for name, chain := range chains {
+		for i := 0; i < 3; i++ {
+			chain(l.Once(name), i).Info(name + " once")
+			chain(l.Every(time.Hour, name+"-every"), i).Info(name + " every")
+			chain(l.FirstN(1, name+"-firstN"), i).Info(name + " firstN")
+		}
+
+		s := h.ResetGet()
+		assert.Eq(t, 1, strings.Count(s, name+" once"), name)
+		assert.Eq(t, 1, strings.Count(s, name+" every"), name)
+		assert.Eq(t, 1, strings.Count(s, name+" firstN"), name)
+	}
+}
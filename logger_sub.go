@@ -29,6 +29,17 @@ type SubLogger struct {
 // NewSubWith returns a new SubLogger with parent logger.
 func NewSubWith(l *Logger) *SubLogger { return &SubLogger{l: l} }
 
+// NewSub returns a new SubLogger that inherits the kept context, fields and data of the sub.
+func (sub *SubLogger) NewSub() *SubLogger {
+	return &SubLogger{
+		l:      sub.l,
+		Ctx:    sub.Ctx,
+		Fields: copyM(sub.Fields),
+		Data:   copyM(sub.Data),
+		Extra:  copyM(sub.Extra),
+	}
+}
+
 // KeepCtx keep context for all log records
 func (sub *SubLogger) KeepCtx(ctx context.Context) *SubLogger {
 	sub.Ctx = ctx
@@ -72,11 +83,15 @@ func (sub *SubLogger) Release() {
 	sub.Extra = nil
 }
 
+// Record returns a new record with the kept context, fields and data.
+func (sub *SubLogger) Record() *Record { return sub.withKeepCtx() }
+
 func (sub *SubLogger) withKeepCtx() *Record {
 	r := sub.l.WithContext(sub.Ctx)
-	r.Data = sub.Data
-	r.Extra = sub.Extra
-	r.Fields = sub.Fields
+	// copy the kept maps, avoid modify them on add fields or data to record. eg: Infow
+	r.Data = copyM(sub.Data)
+	r.Extra = copyM(sub.Extra)
+	r.Fields = copyM(sub.Fields)
 	return r
 }
 
@@ -100,6 +115,9 @@ func (sub *SubLogger) Tracef(format string, args ...any) {
 	sub.withKeepCtx().Tracef(format, args...)
 }
 
+// Tracew logs a message at TraceLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Tracew(msg string, kvs ...any) { sub.withKeepCtx().Tracew(msg, kvs...) }
+
 // Debug logs a message at DebugLevel. will with sub logger's context, fields and data
 func (sub *SubLogger) Debug(args ...any) { sub.withKeepCtx().Debug(args...) }
 
@@ -108,6 +126,9 @@ func (sub *SubLogger) Debugf(format string, args ...any) {
 	sub.withKeepCtx().Debugf(format, args...)
 }
 
+// Debugw logs a message at DebugLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Debugw(msg string, kvs ...any) { sub.withKeepCtx().Debugw(msg, kvs...) }
+
 // Info logs a message at InfoLevel. will with sub logger's context, fields and data
 func (sub *SubLogger) Info(args ...any) { sub.withKeepCtx().Info(args...) }
 
@@ -116,6 +137,9 @@ func (sub *SubLogger) Infof(format string, args ...any) {
 	sub.withKeepCtx().Infof(format, args...)
 }
 
+// Infow logs a message at InfoLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Infow(msg string, kvs ...any) { sub.withKeepCtx().Infow(msg, kvs...) }
+
 // Notice logs a message at NoticeLevel. will with sub logger's context, fields and data
 func (sub *SubLogger) Notice(args ...any) { sub.withKeepCtx().Notice(args...) }
 
@@ -124,6 +148,9 @@ func (sub *SubLogger) Noticef(format string, args ...any) {
 	sub.withKeepCtx().Noticef(format, args...)
 }
 
+// Noticew logs a message at NoticeLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Noticew(msg string, kvs ...any) { sub.withKeepCtx().Noticew(msg, kvs...) }
+
 // Warn logs a message at WarnLevel. will with sub logger's context, fields and data
 func (sub *SubLogger) Warn(args ...any) { sub.withKeepCtx().Warn(args...) }
 
@@ -132,6 +159,9 @@ func (sub *SubLogger) Warnf(format string, args ...any) {
 	sub.withKeepCtx().Warnf(format, args...)
 }
 
+// Warnw logs a message at WarnLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Warnw(msg string, kvs ...any) { sub.withKeepCtx().Warnw(msg, kvs...) }
+
 // Error logs a message at ErrorLevel. will with sub logger's context, fields and data
 func (sub *SubLogger) Error(args ...any) { sub.withKeepCtx().Error(args...) }
 
@@ -140,6 +170,9 @@ func (sub *SubLogger) Errorf(format string, args ...any) {
 	sub.withKeepCtx().Errorf(format, args...)
 }
 
+// Errorw logs a message at ErrorLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Errorw(msg string, kvs ...any) { sub.withKeepCtx().Errorw(msg, kvs...) }
+
 // Fatal logs a message at FatalLevel. will with sub logger's context, fields and data
 func (sub *SubLogger) Fatal(args ...any) { sub.withKeepCtx().Fatal(args...) }
 
@@ -148,6 +181,9 @@ func (sub *SubLogger) Fatalf(format string, args ...any) {
 	sub.withKeepCtx().Fatalf(format, args...)
 }
 
+// Fatalw logs a message at FatalLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Fatalw(msg string, kvs ...any) { sub.withKeepCtx().Fatalw(msg, kvs...) }
+
 // Panic logs a message at PanicLevel. will with sub logger's context, fields and data
 func (sub *SubLogger) Panic(args ...any) { sub.withKeepCtx().Panic(args...) }
 
@@ -155,3 +191,6 @@ func (sub *SubLogger) Panic(args ...any) { sub.withKeepCtx().Panic(args...) }
 func (sub *SubLogger) Panicf(format string, args ...any) {
 	sub.withKeepCtx().Panicf(format, args...)
 }
+
+// Panicw logs a message at PanicLevel with key-values. will with sub logger's context, fields and data
+func (sub *SubLogger) Panicw(msg string, kvs ...any) { sub.withKeepCtx().Panicw(msg, kvs...) }
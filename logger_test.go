@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -415,4 +416,135 @@ func TestLogger_Sub(t *testing.T) {
 	assert.Nil(t, sub.Data)
 	assert.Nil(t, sub.Extra)
 	assert.Nil(t, sub.Fields)
-}
\ No newline at end of file
+}
+func TestLogger_kvMethods(t *testing.T) {
+	h := newTestHandler()
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+
+	var rec *slog.Record
+	h.beforeFormat = func(r *slog.Record) {
+		rec = r.Clone()
+	}
+
+	l.Infow("user login", "user", "inhere", "age", 23)
+	assert.Eq(t, slog.InfoLevel, rec.Level)
+	assert.Eq(t, "user login", rec.Message)
+	assert.Eq(t, slog.M{"user": "inhere", "age": 23}, rec.Data)
+	assert.StrContains(t, rec.Caller.File, "logger_test.go")
+
+	// bad keys
+	l.Warnw("bad keys", "key", "val", 23, "key1", "val1", "odd")
+	assert.Eq(t, slog.M{"key": "val", "key1": "val1", slog.FieldKeyBadKey: []any{23, "odd"}}, rec.Data)
+
+	// with LogField, DataValue
+	l.Errorw("mixed", slog.NewField("ip", "::1"), "key", "val", slog.NewValue("uid", 1))
+	assert.Eq(t, slog.M{"key": "val", "uid": 1}, rec.Data)
+	assert.Eq(t, "::1", rec.Fields["ip"])
+
+	// all levels
+	lvs := []slog.Level{slog.TraceLevel, slog.DebugLevel, slog.NoticeLevel, slog.FatalLevel, slog.PanicLevel}
+	fns := []func(msg string, kvs ...any){l.Tracew, l.Debugw, l.Noticew, l.Fatalw, l.Panicw}
+	for i, fn := range fns {
+		assert.NotPanics(t, func() {
+			fn("level message", nil, "key")
+		})
+		assert.Eq(t, lvs[i], rec.Level)
+		assert.Eq(t, slog.M{slog.FieldKeyBadKey: []any{nil, "key"}}, rec.Data)
+	}
+
+	l.Logw(slog.InfoLevel, "no key-values")
+	assert.Empty(t, rec.Data)
+
+	// to fields
+	l.KeyValuesToFields = true
+	l.Record().Noticew("record message", "key", "val")
+	assert.Eq(t, slog.NoticeLevel, rec.Level)
+	assert.Eq(t, "val", rec.Fields["key"])
+	assert.Empty(t, rec.Data)
+	l.KeyValuesToFields = false
+
+	// sub logger: the kept data is not changed
+	sub := slog.NewSubWith(l).KeepData(slog.M{"app": "demo"})
+	sub.Debugw("sub message", "key", "val")
+	assert.Eq(t, slog.M{"app": "demo", "key": "val"}, rec.Data)
+	sub.Infow("sub message2")
+	assert.Eq(t, slog.M{"app": "demo"}, rec.Data)
+
+	// package level
+	defer slog.Reset()
+	slog.Reset()
+	slog.AddHandler(h)
+	slog.Errorw("std message", "key", "val")
+	assert.Eq(t, slog.ErrorLevel, rec.Level)
+	assert.Eq(t, "val", rec.Data["key"])
+	assert.StrContains(t, rec.Caller.File, "logger_test.go")
+}
+
+func TestLogger_ReplaceHandlers(t *testing.T) {
+	h1 := newTestHandler()
+	h1.errOnHandle = true
+	l := slog.NewWithHandlers(h1)
+	l.ReportCaller = false
+	l.ErrorHandler = slog.DisableOnErrors(1)
+	l.DoNothingOnPanicFatal()
+
+	l.Info("message1")
+	assert.True(t, l.IsDisabledHandler(h1))
+
+	h2 := newTestHandler()
+	old := l.ReplaceHandlers([]slog.Handler{h2})
+	assert.Len(t, old, 1)
+	assert.Eq(t, 1, l.HandlersNum())
+	// the failure states of the old handlers are cleared
+	assert.False(t, l.IsDisabledHandler(h1))
+
+	l.Info("message2")
+	assert.StrContains(t, h2.String(), "message2")
+
+	// async mode: the queued records are handled by the old handlers
+	h3 := newTestHandler()
+	l.EnableAsync()
+	l.Info("message3")
+	l.ReplaceHandlers([]slog.Handler{h3})
+	l.Info("message4")
+	// the error of the h1 is kept as last error
+	assert.ErrMsg(t, l.Flush(), "handle error")
+	assert.StrContains(t, h2.String(), "message3")
+	assert.NotContains(t, h2.String(), "message4")
+	assert.StrContains(t, h3.String(), "message4")
+	assert.ErrMsg(t, l.Close(), "handle error")
+}
+
+func TestLogger_ReplaceHandlersLevel(t *testing.T) {
+	h1 := newTestHandler()
+	l := slog.NewWithHandlers(h1)
+	l.ReportCaller = false
+
+	h2 := newTestHandler()
+	old := l.ReplaceHandlersLevel([]slog.Handler{h2}, slog.WarnLevel)
+	assert.Eq(t, []slog.Handler{h1}, old)
+	assert.Eq(t, slog.WarnLevel, l.MaxLevel())
+
+	// logging on replacing: the new handlers never see the records below the new level
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			l.Info("info message")
+		}
+	}()
+	h3 := newTestHandler()
+	l.ReplaceHandlersLevel([]slog.Handler{h3}, slog.ErrorLevel)
+	wg.Wait()
+
+	l.Error("error message")
+	assert.Empty(t, h2.String())
+	assert.NotContains(t, h3.String(), "info message")
+	assert.StrContains(t, h3.String(), "error message")
+
+	// level=0 will reset the max level
+	l.ReplaceHandlersLevel([]slog.Handler{h3}, 0)
+	assert.Eq(t, slog.TraceLevel, l.MaxLevel())
+}
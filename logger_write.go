@@ -40,17 +40,49 @@ func (r *Record) Init(lowerLevelName bool) {
 	// r.microSecond = r.Time.Nanosecond() / 1000
 }
 
-// Init something for record.
-func (r *Record) beforeHandle(l *Logger) {
+// Init something for record. returns false if the record is dropped by the filters.
+func (r *Record) beforeHandle(l *Logger) bool {
 	// log caller. will alloc 3 times
-	if l.ReportCaller {
+	if l.ReportCaller && !r.callerSet {
 		caller, ok := getCaller(r.CallerSkip)
 		if ok {
 			r.Caller = &caller
 		}
 	}
 
-	// processing log record
+	if r.Level > FatalLevel && !l.allowRecord(r) {
+		return false
+	}
+
+	r.captureStack(l)
+	l.runProcessors(r)
+	return true
+}
+
+// check the record is allowed by the filters. will check the filters of parents first.
+func (l *Logger) allowRecord(r *Record) bool {
+	if l.parent != nil && !l.parent.allowRecord(r) {
+		return false
+	}
+	return AllowRecord(r, l.filters)
+}
+
+// handle the record by the handler. the handler processors will run on a deep copy of the record.
+func (l *Logger) handleRecord(h Handler, r *Record) {
+	if ph, ok := h.(ProcessableHandler); ok {
+		if hp, ok := h.(interface{ HasProcessors() bool }); !ok || hp.HasProcessors() {
+			r = r.DeepClone()
+			ph.ProcessRecord(r)
+		}
+	}
+	l.callHandler(OpHandle, h, r)
+}
+
+// processing log record. will run the processors of parents first.
+func (l *Logger) runProcessors(r *Record) {
+	if l.parent != nil {
+		l.parent.runProcessors(r)
+	}
 	for i := range l.processors {
 		l.processors[i].Process(r)
 	}
@@ -58,24 +90,45 @@ func (r *Record) beforeHandle(l *Logger) {
 
 // do write record to handlers, will add lock.
 func (l *Logger) writeRecord(level Level, r *Record) {
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	if level > FatalLevel && !l.IsLevelEnabled(level) {
+		return
+	}
+	// check the rate limit after the level, the disabled level will not consume it.
+	if !r.allowLimit(level) {
+		return
+	}
+
+	// the named logger without handlers will use handlers of the parent.
+	o := l.handlerOwner()
+	if o.async != nil {
+		// panic, fatal: drain the queue, then handle it synchronously before panic()/Exit()
+		if level > FatalLevel && o.asyncWrite(r) {
+			return
+		}
+		o.drainAsync()
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	// check again, the max level may be changed with the handlers. see ReplaceHandlersLevel()
+	if level > FatalLevel && !l.IsLevelEnabled(level) {
+		return
+	}
 	// reset init flag, useful for repeat use Record
 	r.inited = false
 
-	for _, handler := range l.handlers {
-		if handler.IsHandling(level) {
+	for _, handler := range o.getHandlers() {
+		if handler.IsHandling(level) && !o.isDisabled(handler) {
 			// init record, call processors
 			if !r.inited {
 				r.Init(l.LowerLevelName)
-				r.beforeHandle(l)
+				if !r.beforeHandle(l) {
+					break
+				}
 			}
 
 			// do write a log message by handler
-			if err := handler.Handle(r); err != nil {
-				l.err = err
-				printStderr("slog: failed to handle log, error:", err)
-			}
+			o.handleRecord(handler, r)
 		}
 	}
 
@@ -84,7 +137,7 @@ func (l *Logger) writeRecord(level Level, r *Record) {
 
 	// flush logs on level <= error level.
 	if level <= ErrorLevel {
-		l.flushAll() // has been in lock
+		o.flushAll() // has been in lock
 	}
 
 	if level <= PanicLevel {
@@ -27,7 +27,12 @@ func (fn ProcessorFunc) Process(record *Record) {
 	fn(record)
 }
 
-// ProcessableHandler interface
+// ProcessableHandler interface. the handler processors will run on a deep copy of
+// the record before the handler handle it, the changes will not affect other handlers.
+// see Record.DeepClone for the copied nested types.
+//
+// TIP: the handler can implement the HasProcessors() bool method to avoid copy the record
+// if there are no processors. the Processable has implemented it.
 type ProcessableHandler interface {
 	// AddProcessor add a processor
 	AddProcessor(Processor)
@@ -35,7 +40,12 @@ type ProcessableHandler interface {
 	ProcessRecord(record *Record)
 }
 
-// Processable definition
+// Processable definition. can be embedded in the handler for support the handler processors.
+//
+// Usage:
+//
+//	h := handler.NewIOWriter(w, slog.AllLevels)
+//	h.AddProcessor(slog.AddHostname())
 type Processable struct {
 	processors []Processor
 }
@@ -45,6 +55,11 @@ func (p *Processable) AddProcessor(processor Processor) {
 	p.processors = append(p.processors, processor)
 }
 
+// HasProcessors check has processors
+func (p *Processable) HasProcessors() bool {
+	return len(p.processors) > 0
+}
+
 // ProcessRecord process record
 func (p *Processable) ProcessRecord(r *Record) {
 	// processing log record
@@ -103,6 +118,8 @@ func AppendCtxKeys(keys ...string) Processor {
 
 // CtxKeysProcessor append context keys to Record.Data, Record.Fields, Record.Extra
 //  - dist: "data" | "fields" | "extra"
+//
+// TIP: the plain string keys are collision-prone, recommend use ContextKeysProcessor with ContextKey.
 func CtxKeysProcessor(dist string, keys ...string) Processor {
 	return ProcessorFunc(func(r *Record) {
 		if r.Ctx == nil {
@@ -115,14 +132,44 @@ func CtxKeysProcessor(dist string, keys ...string) Processor {
 				kvMap[key] = val
 			}
 		}
+		addToDist(r, dist, kvMap)
+	})
+}
 
-		switch dist {
-		case "field", "fields":
-			r.AddFields(kvMap)
-		case "ext", "extra":
-			r.AddExtra(kvMap)
-		default:
-			r.AddData(kvMap)
+// ContextKeysProcessor append the values of typed context keys to Record.Data, Record.Fields, Record.Extra
+//  - dist: "data" | "fields" | "extra"
+//
+// Usage:
+//
+//	var RequestID = slog.NewContextKey[string]("request_id")
+//	logger.AddProcessor(slog.ContextKeysProcessor("fields", RequestID))
+func ContextKeysProcessor(dist string, keys ...CtxKey) Processor {
+	return ProcessorFunc(func(r *Record) {
+		if r.Ctx == nil {
+			return
+		}
+
+		kvMap := make(map[string]any, len(keys))
+		for _, key := range keys {
+			if val, ok := key.AnyValue(r.Ctx); ok {
+				kvMap[key.Name()] = val
+			}
 		}
+		addToDist(r, dist, kvMap)
 	})
 }
+
+func addToDist(r *Record, dist string, kvMap M) {
+	if len(kvMap) == 0 {
+		return
+	}
+
+	switch dist {
+	case "field", "fields":
+		r.AddFields(kvMap)
+	case "ext", "extra":
+		r.AddExtra(kvMap)
+	default:
+		r.AddData(kvMap)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/gookit/goutil/byteutil"
 	"github.com/gookit/goutil/testutil/assert"
 	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
 )
 
 func TestLogger_AddProcessor(t *testing.T) {
@@ -113,3 +114,84 @@ func TestProcessable_AddProcessor(t *testing.T) {
 	assert.NotEmpty(t, r.Extra)
 	assert.Contains(t, r.Extra, "memoryUsage")
 }
+
+func TestLogger_handlerProcessors(t *testing.T) {
+	buf1 := new(byteutil.Buffer)
+	buf2 := new(byteutil.Buffer)
+
+	h1 := handler.NewIOWriter(buf1, slog.AllLevels)
+	h1.SetFormatter(slog.NewJSONFormatter())
+	h1.AddProcessor(slog.AddHostname())
+	h1.AddProcessor(slog.ProcessorFunc(func(r *slog.Record) {
+		r.Message = "changed: " + r.Message
+		r.Fields["user"] = "***"
+	}))
+	h2 := handler.NewIOWriter(buf2, slog.AllLevels)
+	h2.SetFormatter(slog.NewTextFormatter("{{message}} {{user}}\n"))
+
+	var rec *slog.Record
+	l := slog.NewWithHandlers(h1, h2, &recordCopyHandler{fn: func(r *slog.Record) { rec = r }})
+	l.ReportCaller = false
+	l.WithFields(slog.M{"user": "inhere"}).Info("message")
+
+	hostname, _ := os.Hostname()
+	assert.StrContains(t, buf1.String(), `"message":"changed: message"`)
+	assert.StrContains(t, buf1.String(), `"user":"***"`)
+	assert.StrContains(t, buf1.String(), fmt.Sprintf(`"hostname":"%s"`, hostname))
+	// the changes does not leak into other handlers
+	assert.Eq(t, "message inhere\n", buf2.String())
+	assert.Eq(t, "message", rec.Message)
+	assert.Eq(t, slog.M{"user": "inhere"}, rec.Fields)
+
+	// async mode
+	buf1.Reset()
+	buf2.Reset()
+	l.EnableAsync()
+	l.WithFields(slog.M{"user": "inhere"}).Info("async message")
+	assert.NoErr(t, l.Close())
+	assert.StrContains(t, buf1.String(), `"message":"changed: async message"`)
+	assert.Eq(t, "async message inhere\n", buf2.String())
+}
+
+func TestLogger_handlerProcessors_nested(t *testing.T) {
+	buf1 := new(byteutil.Buffer)
+	buf2 := new(byteutil.Buffer)
+
+	h1 := handler.NewIOWriter(buf1, slog.AllLevels)
+	h1.SetFormatter(slog.NewJSONFormatter())
+	h1.AddProcessor(slog.ProcessorFunc(func(r *slog.Record) {
+		r.Data["user"].(map[string]any)["password"] = "***"
+		r.Data["tags"].([]any)[0] = "***"
+	}))
+	h2 := handler.NewIOWriter(buf2, slog.AllLevels)
+	h2.SetFormatter(slog.NewJSONFormatter())
+
+	l := slog.NewWithHandlers(h1, h2)
+	l.ReportCaller = false
+	l.WithData(slog.M{
+		"user": map[string]any{"name": "inhere", "password": "secret"},
+		"tags": []any{"secret-tag"},
+	}).Info("message")
+
+	assert.StrContains(t, buf1.String(), `"password":"***"`)
+	assert.StrContains(t, buf1.String(), `"tags":["***"]`)
+	// the changes of the nested values does not leak into other handlers
+	assert.StrContains(t, buf2.String(), `"password":"secret"`)
+	assert.StrContains(t, buf2.String(), `"tags":["secret-tag"]`)
+}
+
+// recordCopyHandler call the fn with a copy of the handled record
+type recordCopyHandler struct {
+	fn func(r *slog.Record)
+}
+
+func (h *recordCopyHandler) IsHandling(_ slog.Level) bool { return true }
+
+func (h *recordCopyHandler) Handle(r *slog.Record) error {
+	h.fn(r.Clone())
+	return nil
+}
+
+func (h *recordCopyHandler) Flush() error { return nil }
+
+func (h *recordCopyHandler) Close() error { return nil }
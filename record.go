@@ -28,6 +28,10 @@ type Record struct {
 	freed bool
 	// inited flag for record
 	inited bool
+	// skip report caller, the Caller has been set. eg: by StdlibHandler
+	callerSet bool
+	// the pending rate limit, checked on writing. see Logger.Once()
+	limit *recordLimit
 
 	// Time for record log, if is empty will use now.
 	//
@@ -59,8 +63,12 @@ type Record struct {
 	CallerFlag uint8
 	// CallerSkip value. default is equals to Logger.CallerSkip
 	CallerSkip int
-	// EnableStack enable stack info, default is false. TODO
+	// EnableStack capture the call stack for the record, default is false.
+	//
+	// TIP: the stack will be captured for all records at or above the Logger.StackLevel
 	EnableStack bool
+	// Stack the captured call stack, output as FieldKeyStacktrace.
+	Stack []StackFrame
 
 	// Buffer Can use Buffer on formatter
 	// Buffer *bytes.Buffer
@@ -68,6 +76,9 @@ type Record struct {
 	// log input args backups, from log() and logf(). its dont use in formatter.
 	Fmt  string
 	Args []any
+
+	// typed fields added by the builder methods. eg: Str, Int
+	typed []Field
 }
 
 func newRecord(logger *Logger) *Record {
@@ -186,7 +197,118 @@ func (r *Record) Copy() *Record {
 		Data:       dataCopy,
 		Extra:      extraCopy,
 		Fields:     fieldsCopy,
+		typed:      copyFields(r.typed),
+		limit:      r.limit,
+	}
+}
+
+// Clone create a full copy of the record, include Time, Ctx, Caller and more.
+//
+// The Fields, Data and Extra are shallow copied. The clone record will not be reused,
+// it is useful for handle the record on another goroutine.
+func (r *Record) Clone() *Record {
+	nr := *r
+	nr.reuse = false
+	nr.freed = false
+
+	nr.Fields = copyM(r.Fields)
+	nr.Data = copyM(r.Data)
+	nr.Extra = copyM(r.Extra)
+	nr.typed = copyFields(r.typed)
+	return &nr
+}
+
+// DeepClone create a full copy of the record like Clone, and the nested maps and slices
+// in the Fields, Data, Extra and typed fields are copied too. The Caller and Stack are copied.
+//
+// Supported nested types: M, map[string]any, map[string]string, []any, []string.
+// Other values(eg: pointer, struct) are shared with the original record.
+func (r *Record) DeepClone() *Record {
+	nr := r.Clone()
+	nr.Fields = deepCopyM(r.Fields)
+	nr.Data = deepCopyM(r.Data)
+	nr.Extra = deepCopyM(r.Extra)
+	for i := range nr.typed {
+		if nr.typed[i].val != nil {
+			nr.typed[i].val = deepCopyValue(nr.typed[i].val)
+		}
+	}
+
+	if r.Caller != nil {
+		caller := *r.Caller
+		nr.Caller = &caller
+	}
+	if len(r.Stack) > 0 {
+		nr.Stack = append([]StackFrame(nil), r.Stack...)
+	}
+	return nr
+}
+
+func deepCopyM(m M) M {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(M, len(m))
+	for k, v := range m {
+		nm[k] = deepCopyValue(v)
+	}
+	return nm
+}
+
+func deepCopyValue(v any) any {
+	switch tv := v.(type) {
+	case M:
+		return deepCopyM(tv)
+	case map[string]any:
+		if tv == nil {
+			return tv
+		}
+		return map[string]any(deepCopyM(tv))
+	case map[string]string:
+		if tv == nil {
+			return tv
+		}
+		nm := make(map[string]string, len(tv))
+		for k, s := range tv {
+			nm[k] = s
+		}
+		return nm
+	case []any:
+		if tv == nil {
+			return tv
+		}
+		ns := make([]any, len(tv))
+		for i, ev := range tv {
+			ns[i] = deepCopyValue(ev)
+		}
+		return ns
+	case []string:
+		if tv == nil {
+			return tv
+		}
+		return append([]string(nil), tv...)
 	}
+	return v
+}
+
+func copyFields(fs []Field) []Field {
+	if len(fs) == 0 {
+		return nil
+	}
+	return append([]Field(nil), fs...)
+}
+
+func copyM(m M) M {
+	if m == nil {
+		return nil
+	}
+
+	nm := make(M, len(m))
+	for k, v := range m {
+		nm[k] = v
+	}
+	return nm
 }
 
 //
@@ -316,32 +438,27 @@ func (r *Record) Field(key string) any {
 	return r.Fields[key]
 }
 
-//
-// ---------------------------------------------------------------------------
-// Add log message with builder
-// TODO r.Build(InfoLevel).Str().Int().Float().Msg()
-// ---------------------------------------------------------------------------
-//
-
-// Object data on record TODO optimize performance
-// func (r *Record) Obj(obj fmt.Stringer) *Record {
-// 	r.Data = ctx
-// 	return r
-// }
+// Lookup the value by key from the record. will find in order:
+// Record.Fields, the typed fields, Record.Data, Record.Extra
+func (r *Record) Lookup(key string) (any, bool) {
+	if v, ok := r.Fields[key]; ok {
+		return v, true
+	}
 
-// Object data on record TODO optimize performance
-// func (r *Record) Any(v any) *Record {
-// 	r.Data = ctx
-// 	return r
-// }
+	for i := 0; i < len(r.typed); i++ {
+		if r.typed[i].Key == key {
+			return fieldValue(r.typed, i), true
+		}
+		i += r.typed[i].Span()
+	}
 
-// func (r *Record) Str(message string) {
-// 	r.logWrite(level, []byte(message))
-// }
+	if v, ok := r.Data[key]; ok {
+		return v, true
+	}
 
-// func (r *Record) Int(val int) {
-// 	r.logWrite(level, []byte(message))
-// }
+	v, ok := r.Extra[key]
+	return v, ok
+}
 
 //
 // ---------------------------------------------------------------------------
@@ -356,7 +473,7 @@ func (r *Record) log(level Level, args []any) {
 	}
 
 	// r.Message = strutil.Byte2str(formatArgsWithSpaces(args)) // will reduce memory allocation once
-	r.Message = formatArgsWithSpaces(args)
+	r.Message = formatArgsWithSpaces(r.extractArgs(args))
 	// do write log, then release record
 	r.logger.writeRecord(level, r)
 	r.logger.releaseRecord(r)
@@ -367,6 +484,11 @@ func (r *Record) logf(level Level, format string, args []any) {
 		r.Fmt, r.Args = format, args
 	}
 
+	// only extract the trailing args beyond the verbs, the others are used by the verbs.
+	if n, ok := countFormatArgs(format); ok && n < len(args) {
+		args = append(args[:n:n], r.extractArgs(args[n:])...)
+	}
+
 	r.Level = level
 	r.Message = fmt.Sprintf(format, args...)
 	// do write log, then release record
@@ -374,6 +496,91 @@ func (r *Record) logf(level Level, format string, args []any) {
 	r.logger.releaseRecord(r)
 }
 
+func (r *Record) logw(level Level, msg string, kvs []any) {
+	if r.logger.BackupArgs {
+		r.Args = kvs
+	}
+
+	r.Level = level
+	r.Message = msg
+	r.addKeyValues(kvs)
+	// do write log, then release record
+	r.logger.writeRecord(level, r)
+	r.logger.releaseRecord(r)
+}
+
+// addKeyValues add alternating key-values to Data, or Fields on Logger.KeyValuesToFields=true.
+//
+// The LogField, DataValue are added as extractArgs(). The value of odd key and the
+// non-string keys are collected to []any under the FieldKeyBadKey.
+func (r *Record) addKeyValues(kvs []any) {
+	var bad []any
+	for i := 0; i < len(kvs); i++ {
+		switch k := kvs[i].(type) {
+		case string:
+			if i+1 < len(kvs) {
+				r.addKeyValue(k, kvs[i+1])
+				i++
+			} else {
+				bad = append(bad, k)
+			}
+		case LogField:
+			r.AddField(k.Key, k.Val)
+		case DataValue:
+			r.AddValue(k.Key, k.Val)
+		default:
+			bad = append(bad, k)
+		}
+	}
+
+	if len(bad) > 0 {
+		r.addKeyValue(FieldKeyBadKey, bad)
+	}
+}
+
+func (r *Record) addKeyValue(key string, val any) {
+	if r.logger.KeyValuesToFields {
+		r.AddField(key, val)
+	} else {
+		r.AddValue(key, val)
+	}
+}
+
+// extractArgs add the LogField, DataValue in args to Fields, Data. returns the remaining args.
+//
+// will not alloc new slice if there is no LogField, DataValue in args.
+func (r *Record) extractArgs(args []any) []any {
+	var rest []any
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case LogField:
+			r.AddField(v.Key, v.Val)
+		case *LogField:
+			r.AddField(v.Key, v.Val)
+		case DataValue:
+			r.AddValue(v.Key, v.Val)
+		case *DataValue:
+			r.AddValue(v.Key, v.Val)
+		default:
+			if rest != nil {
+				rest = append(rest, arg)
+			}
+			continue
+		}
+
+		// first found, copy the previous args
+		if rest == nil {
+			rest = make([]any, i, len(args)-1)
+			copy(rest, args[:i])
+		}
+	}
+
+	if rest == nil {
+		return args
+	}
+	return rest
+}
+
 // Log a message with level
 func (r *Record) Log(level Level, args ...any) { r.log(level, args) }
 
@@ -382,6 +589,9 @@ func (r *Record) Logf(level Level, format string, args ...any) {
 	r.logf(level, format, args)
 }
 
+// Logw logs a message with level and key-values. eg: r.Logw(slog.InfoLevel, "msg", "key", "val")
+func (r *Record) Logw(level Level, msg string, kvs ...any) { r.logw(level, msg, kvs) }
+
 // Info logs a message at level Info
 func (r *Record) Info(args ...any) { r.log(InfoLevel, args) }
 
@@ -390,6 +600,9 @@ func (r *Record) Infof(format string, args ...any) {
 	r.logf(InfoLevel, format, args)
 }
 
+// Infow logs a message at level Info with key-values
+func (r *Record) Infow(msg string, kvs ...any) { r.logw(InfoLevel, msg, kvs) }
+
 // Trace logs a message at level Trace
 func (r *Record) Trace(args ...any) { r.log(TraceLevel, args) }
 
@@ -398,6 +611,9 @@ func (r *Record) Tracef(format string, args ...any) {
 	r.logf(TraceLevel, format, args)
 }
 
+// Tracew logs a message at level Trace with key-values
+func (r *Record) Tracew(msg string, kvs ...any) { r.logw(TraceLevel, msg, kvs) }
+
 // Error logs a message at level Error
 func (r *Record) Error(args ...any) { r.log(ErrorLevel, args) }
 
@@ -406,6 +622,9 @@ func (r *Record) Errorf(format string, args ...any) {
 	r.logf(ErrorLevel, format, args)
 }
 
+// Errorw logs a message at level Error with key-values
+func (r *Record) Errorw(msg string, kvs ...any) { r.logw(ErrorLevel, msg, kvs) }
+
 // Warn logs a message at level Warn
 func (r *Record) Warn(args ...any) { r.log(WarnLevel, args) }
 
@@ -414,6 +633,9 @@ func (r *Record) Warnf(format string, args ...any) {
 	r.logf(WarnLevel, format, args)
 }
 
+// Warnw logs a message at level Warn with key-values
+func (r *Record) Warnw(msg string, kvs ...any) { r.logw(WarnLevel, msg, kvs) }
+
 // Notice logs a message at level Notice
 func (r *Record) Notice(args ...any) { r.log(NoticeLevel, args) }
 
@@ -422,6 +644,9 @@ func (r *Record) Noticef(format string, args ...any) {
 	r.logf(NoticeLevel, format, args)
 }
 
+// Noticew logs a message at level Notice with key-values
+func (r *Record) Noticew(msg string, kvs ...any) { r.logw(NoticeLevel, msg, kvs) }
+
 // Debug logs a message at level Debug
 func (r *Record) Debug(args ...any) { r.log(DebugLevel, args) }
 
@@ -430,6 +655,9 @@ func (r *Record) Debugf(format string, args ...any) {
 	r.logf(DebugLevel, format, args)
 }
 
+// Debugw logs a message at level Debug with key-values
+func (r *Record) Debugw(msg string, kvs ...any) { r.logw(DebugLevel, msg, kvs) }
+
 // Print logs a message at level Print
 func (r *Record) Print(args ...any) { r.log(PrintLevel, args) }
 
@@ -452,6 +680,9 @@ func (r *Record) Fatalf(format string, args ...any) {
 	r.logf(FatalLevel, format, args)
 }
 
+// Fatalw logs a message at level Fatal with key-values
+func (r *Record) Fatalw(msg string, kvs ...any) { r.logw(FatalLevel, msg, kvs) }
+
 // Panic logs a message at level Panic
 func (r *Record) Panic(args ...any) { r.log(PanicLevel, args) }
 
@@ -463,6 +694,9 @@ func (r *Record) Panicf(format string, args ...any) {
 	r.logf(PanicLevel, format, args)
 }
 
+// Panicw logs a message at level Panic with key-values
+func (r *Record) Panicw(msg string, kvs ...any) { r.logw(PanicLevel, msg, kvs) }
+
 // ---------------------------------------------------------------------------
 // helper methods
 // ---------------------------------------------------------------------------
@@ -0,0 +1,401 @@
+package slog
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// FieldKind the value kind of the typed Field
+type FieldKind uint8
+
+// there are kinds of the typed Field
+const (
+	FieldKindAny FieldKind = iota
+	FieldKindString
+	FieldKindInt
+	FieldKindUint
+	FieldKindFloat
+	FieldKindBool
+	FieldKindDuration
+	FieldKindTime
+	FieldKindError
+	FieldKindBytes
+	FieldKindDict
+	FieldKindArray
+)
+
+// Field a typed field, added by the builder methods of the Record. eg: Str, Int
+//
+// The fields are stored in a flat list, the nested fields of a Dict or Array
+// are placed after it, use Span() to get the number of them.
+type Field struct {
+	// Key name, it is empty for the element of an Array
+	Key  string
+	Kind FieldKind
+
+	num uint64
+	str string
+	val any
+}
+
+// Span get the number of nested fields after the Dict or Array field, include deeper levels.
+func (f *Field) Span() int {
+	if f.Kind == FieldKindDict || f.Kind == FieldKindArray {
+		return int(f.num)
+	}
+	return 0
+}
+
+// Str value of the FieldKindString
+func (f *Field) Str() string { return f.str }
+
+// Int64 value of the FieldKindInt
+func (f *Field) Int64() int64 { return int64(f.num) }
+
+// Uint64 value of the FieldKindUint
+func (f *Field) Uint64() uint64 { return f.num }
+
+// Float64 value of the FieldKindFloat
+func (f *Field) Float64() float64 { return math.Float64frombits(f.num) }
+
+// Bool value of the FieldKindBool
+func (f *Field) Bool() bool { return f.num == 1 }
+
+// Duration value of the FieldKindDuration
+func (f *Field) Duration() time.Duration { return time.Duration(f.num) }
+
+// Time value of the FieldKindTime
+func (f *Field) Time() time.Time {
+	if loc, ok := f.val.(*time.Location); ok {
+		return time.Unix(0, int64(f.num)).In(loc)
+	}
+	return time.Time{}
+}
+
+// Err value of the FieldKindError, maybe is nil.
+func (f *Field) Err() error {
+	err, _ := f.val.(error)
+	return err
+}
+
+// Bytes value of the FieldKindBytes
+func (f *Field) Bytes() []byte {
+	bs, _ := f.val.([]byte)
+	return bs
+}
+
+// Value get the boxed value of the field. the Dict and Array will return nil, please use FieldsToMap().
+func (f *Field) Value() any {
+	switch f.Kind {
+	case FieldKindString:
+		return f.str
+	case FieldKindInt:
+		return f.Int64()
+	case FieldKindUint:
+		return f.num
+	case FieldKindFloat:
+		return f.Float64()
+	case FieldKindBool:
+		return f.Bool()
+	case FieldKindDuration:
+		return f.Duration()
+	case FieldKindTime:
+		return f.Time()
+	case FieldKindDict, FieldKindArray:
+		return nil
+	default: // FieldKindAny, FieldKindError, FieldKindBytes
+		return f.val
+	}
+}
+
+// FieldsToMap convert the typed fields to M. the Dict will be converted to M, Array to []any
+func FieldsToMap(fs []Field) M {
+	m := make(M, len(fs))
+	for i := 0; i < len(fs); i++ {
+		m[fs[i].Key] = fieldValue(fs, i)
+		i += fs[i].Span()
+	}
+	return m
+}
+
+// get the value of fs[i], will convert nested fields.
+func fieldValue(fs []Field, i int) any {
+	f := &fs[i]
+	switch f.Kind {
+	case FieldKindDict:
+		return FieldsToMap(fs[i+1 : i+1+f.Span()])
+	case FieldKindArray:
+		sub := fs[i+1 : i+1+f.Span()]
+		arr := make([]any, 0, len(sub))
+		for j := 0; j < len(sub); j++ {
+			arr = append(arr, fieldValue(sub, j))
+			j += sub[j].Span()
+		}
+		return arr
+	default:
+		return f.Value()
+	}
+}
+
+//
+// ---------------------------------------------------------------------------
+// region Add log message with builder
+// ---------------------------------------------------------------------------
+//
+
+// Build start to build a log message with typed fields for the level.
+//
+// The typed fields are stored without map allocation and understood natively by
+// JSONFormatter and TextFormatter. Returns nil if the level is disabled, all
+// builder methods are nil safe.
+//
+// Usage:
+//
+//	logger.Build(slog.InfoLevel).
+//		Str("user", "inhere").
+//		Int("age", 23).
+//		Dur("cost", cost).
+//		Msg("user login")
+func (l *Logger) Build(level Level) *Record {
+	if !l.shouldHandle(level) {
+		return nil
+	}
+
+	r := l.newRecord()
+	r.Level = level
+	return r
+}
+
+// Build start to build a log message with typed fields for the level. see Logger.Build()
+//
+// Usage:
+//
+//	r.Build(slog.InfoLevel).Str("key", "val").Int("num", 23).Msg("some message")
+func (r *Record) Build(level Level) *Record {
+	if r == nil || !r.logger.shouldHandle(level) {
+		return nil
+	}
+	if !r.allowLimit(level) {
+		return nil
+	}
+
+	r.Level = level
+	return r
+}
+
+// TypedFields get the typed fields added by the builder methods. eg: Str, Int
+func (r *Record) TypedFields() []Field { return r.typed }
+
+// Msg write the log message. will release the record after writing.
+func (r *Record) Msg(msg string) {
+	if r != nil {
+		r.Message = msg
+		r.send()
+	}
+}
+
+// Msgf write the log message with format. will release the record after writing.
+func (r *Record) Msgf(format string, args ...any) {
+	if r != nil {
+		r.Message = fmt.Sprintf(format, args...)
+		r.send()
+	}
+}
+
+// Send write the log without message. will release the record after writing.
+func (r *Record) Send() {
+	if r != nil {
+		r.Message = ""
+		r.send()
+	}
+}
+
+func (r *Record) send() {
+	r.logger.writeRecord(r.Level, r)
+	r.logger.releaseRecord(r)
+}
+
+func (r *Record) addTyped(f Field) *Record {
+	if r != nil {
+		r.typed = append(r.typed, f)
+	}
+	return r
+}
+
+// Str add a string field
+func (r *Record) Str(key, val string) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindString, str: val})
+}
+
+// Int add an int field
+func (r *Record) Int(key string, val int) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindInt, num: uint64(val)})
+}
+
+// Int64 add an int64 field
+func (r *Record) Int64(key string, val int64) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindInt, num: uint64(val)})
+}
+
+// Uint add an uint field
+func (r *Record) Uint(key string, val uint) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindUint, num: uint64(val)})
+}
+
+// Uint64 add an uint64 field
+func (r *Record) Uint64(key string, val uint64) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindUint, num: val})
+}
+
+// Float add a float64 field
+func (r *Record) Float(key string, val float64) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindFloat, num: math.Float64bits(val)})
+}
+
+// Bool add a bool field
+func (r *Record) Bool(key string, val bool) *Record {
+	return r.addTyped(newBoolField(key, val))
+}
+
+// Dur add a time.Duration field
+func (r *Record) Dur(key string, val time.Duration) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindDuration, num: uint64(val)})
+}
+
+// TimeField add a time.Time field, it is formatted by the TimeFormat of the formatter.
+func (r *Record) TimeField(key string, val time.Time) *Record {
+	return r.addTyped(newTimeField(key, val))
+}
+
+// Err add an error field, the key is FieldKeyError
+func (r *Record) Err(err error) *Record {
+	return r.addTyped(Field{Key: FieldKeyError, Kind: FieldKindError, val: err})
+}
+
+// NamedErr add an error field with key
+func (r *Record) NamedErr(key string, err error) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindError, val: err})
+}
+
+// Bytes add a []byte field, it is output as a string.
+func (r *Record) Bytes(key string, val []byte) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindBytes, val: val})
+}
+
+// Any add a field with any value. NOTE: the value will be boxed, please use typed methods if possible.
+func (r *Record) Any(key string, val any) *Record {
+	return r.addTyped(Field{Key: key, Kind: FieldKindAny, val: val})
+}
+
+// Dict add a nested object field, the fields added in fn will be nested in it.
+//
+// Usage:
+//
+//	r.Dict("user", func(r *slog.Record) {
+//		r.Str("name", "inhere").Int("age", 23)
+//	})
+func (r *Record) Dict(key string, fn func(r *Record)) *Record {
+	if r == nil {
+		return r
+	}
+
+	idx := len(r.typed)
+	r.typed = append(r.typed, Field{Key: key, Kind: FieldKindDict})
+	fn(r)
+	r.typed[idx].num = uint64(len(r.typed) - idx - 1)
+	return r
+}
+
+// Array add an array field, the elements added in fn will be nested in it.
+//
+// Usage:
+//
+//	r.Array("tags", func(a slog.Array) {
+//		a.Str("go").Str("log").Int(23)
+//	})
+func (r *Record) Array(key string, fn func(a Array)) *Record {
+	if r == nil {
+		return r
+	}
+
+	idx := len(r.typed)
+	r.typed = append(r.typed, Field{Key: key, Kind: FieldKindArray})
+	fn(Array{r: r})
+	r.typed[idx].num = uint64(len(r.typed) - idx - 1)
+	return r
+}
+
+// Array builder for the array elements. see Record.Array()
+type Array struct {
+	r *Record
+}
+
+func (a Array) add(f Field) Array {
+	a.r.typed = append(a.r.typed, f)
+	return a
+}
+
+// Str add a string element
+func (a Array) Str(val string) Array { return a.add(Field{Kind: FieldKindString, str: val}) }
+
+// Int add an int element
+func (a Array) Int(val int) Array { return a.add(Field{Kind: FieldKindInt, num: uint64(val)}) }
+
+// Int64 add an int64 element
+func (a Array) Int64(val int64) Array { return a.add(Field{Kind: FieldKindInt, num: uint64(val)}) }
+
+// Uint add an uint element
+func (a Array) Uint(val uint) Array { return a.add(Field{Kind: FieldKindUint, num: uint64(val)}) }
+
+// Float add a float64 element
+func (a Array) Float(val float64) Array {
+	return a.add(Field{Kind: FieldKindFloat, num: math.Float64bits(val)})
+}
+
+// Bool add a bool element
+func (a Array) Bool(val bool) Array { return a.add(newBoolField("", val)) }
+
+// Dur add a time.Duration element
+func (a Array) Dur(val time.Duration) Array {
+	return a.add(Field{Kind: FieldKindDuration, num: uint64(val)})
+}
+
+// Time add a time.Time element
+func (a Array) Time(val time.Time) Array { return a.add(newTimeField("", val)) }
+
+// Err add an error element
+func (a Array) Err(err error) Array { return a.add(Field{Kind: FieldKindError, val: err}) }
+
+// Any add an element with any value
+func (a Array) Any(val any) Array { return a.add(Field{Kind: FieldKindAny, val: val}) }
+
+// Dict add a nested object element
+func (a Array) Dict(fn func(r *Record)) Array {
+	a.r.Dict("", fn)
+	return a
+}
+
+// Array add a nested array element
+func (a Array) Array(fn func(a Array)) Array {
+	a.r.Array("", fn)
+	return a
+}
+
+func newBoolField(key string, val bool) Field {
+	f := Field{Key: key, Kind: FieldKindBool}
+	if val {
+		f.num = 1
+	}
+	return f
+}
+
+func newTimeField(key string, val time.Time) Field {
+	f := Field{Key: key, Kind: FieldKindTime}
+	// the zero time will keep val is nil
+	if !val.IsZero() {
+		f.num = uint64(val.UnixNano())
+		f.val = val.Location()
+	}
+	return f
+}
@@ -0,0 +1,224 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/handler"
+)
+
+func TestLogger_Build(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+
+	tm := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+	l.Build(slog.InfoLevel).
+		Str("str", "val").
+		Int("int", -23).
+		Int64("int64", 64).
+		Uint("uint", 23).
+		Uint64("uint64", 64).
+		Float("float", 1.5).
+		Bool("bool", true).
+		Dur("dur", 1500*time.Millisecond).
+		TimeField("time", tm).
+		Err(errors.New("an error")).
+		NamedErr("nil_err", nil).
+		Bytes("bytes", []byte("abc")).
+		Any("any", []int{1, 2}).
+		Dict("dict", func(r *slog.Record) {
+			r.Str("name", "inhere").Dict("sub", func(r *slog.Record) {
+				r.Int("age", 23)
+			})
+		}).
+		Array("arr", func(a slog.Array) {
+			a.Str("a").Int(1).Bool(false).Dict(func(r *slog.Record) {
+				r.Str("key", "val")
+			}).Array(func(a slog.Array) {
+				a.Float(2.5).Uint(3)
+			})
+		}).
+		Str("after", "nested").
+		Msg("typed fields message")
+
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(h.Bytes(), &m))
+	assert.Eq(t, "typed fields message", m["message"])
+	assert.Eq(t, "val", m["str"])
+	assert.Eq(t, float64(-23), m["int"])
+	assert.Eq(t, float64(64), m["int64"])
+	assert.Eq(t, float64(23), m["uint"])
+	assert.Eq(t, 1.5, m["float"])
+	assert.Eq(t, true, m["bool"])
+	assert.Eq(t, "1.5s", m["dur"])
+	// the typed time is formatted by the TimeFormat
+	assert.Eq(t, tm.Format(slog.DefaultTimeFormat), m["time"])
+	assert.Eq(t, []any{map[string]any{"message": "an error", "type": "*errors.errorString"}}, m["error"])
+	assert.Nil(t, m["nil_err"])
+	assert.Eq(t, "abc", m["bytes"])
+	assert.Eq(t, []any{float64(1), float64(2)}, m["any"])
+	assert.Eq(t, map[string]any{"name": "inhere", "sub": map[string]any{"age": float64(23)}}, m["dict"])
+	assert.Eq(t, []any{"a", float64(1), false, map[string]any{"key": "val"}, []any{2.5, float64(3)}}, m["arr"])
+	assert.Eq(t, "nested", m["after"])
+	assert.StrContains(t, m["caller"].(string), "record_field_test.go")
+
+	// text formatter
+	h.Reset()
+	h.SetFormatter(slog.NewTextFormatter("{{message}}\n"))
+	l.Build(slog.WarnLevel).
+		Str("str", "has space").
+		Int("int", 23).
+		Dict("dict", func(r *slog.Record) {
+			r.Str("name", "inhere").Int("age", 23)
+		}).
+		Array("arr", func(a slog.Array) {
+			a.Str("a").Int(1)
+		}).
+		Err(errors.New("an error")).
+		Msgf("text %s", "message")
+	assert.Eq(t, "text message str=\"has space\" int=23 dict={name=inhere age=23} arr=[a 1] error=\"an error\"\n", h.String())
+
+	// the fields are reset after write
+	h.Reset()
+	l.Build(slog.InfoLevel).Send()
+	assert.Eq(t, "\n", h.String())
+}
+
+func TestLogger_Build_disabled(t *testing.T) {
+	h := newTestHandler()
+	l := slog.NewWithHandlers(handler.NewIOWriter(h, []slog.Level{slog.ErrorLevel}))
+
+	r := l.Build(slog.InfoLevel)
+	assert.Nil(t, r)
+	assert.NotPanics(t, func() {
+		r.Str("key", "val").
+			Int("num", 1).
+			Dict("dict", func(r *slog.Record) {
+				r.Str("key", "val")
+			}).
+			Array("arr", func(a slog.Array) {
+				a.Str("val")
+			}).
+			Msg("message")
+		r.Send()
+		r.Msgf("message %s", "val")
+	})
+	assert.Empty(t, h.String())
+
+	r = l.Record().Build(slog.ErrorLevel)
+	assert.NotNil(t, r)
+	r.Str("key", "val").Msg("error message")
+	assert.StrContains(t, h.String(), "error message key=val")
+}
+
+func TestFieldsToMap(t *testing.T) {
+	l := slog.NewWithHandlers(newTestHandler())
+	r := l.Record().Build(slog.InfoLevel).
+		Str("str", "val").
+		TimeField("zero", time.Time{}).
+		Float("nan", math.NaN()).
+		Dict("dict", func(r *slog.Record) {
+			r.Int("age", 23)
+		}).
+		Array("arr", func(a slog.Array) {
+			a.Dur(time.Second).Err(nil)
+		})
+
+	fs := r.TypedFields()
+	assert.Len(t, fs, 8)
+	assert.Eq(t, 1, fs[3].Span())
+	assert.Eq(t, 2, fs[5].Span())
+	assert.Eq(t, 0, fs[0].Span())
+	assert.Eq(t, "val", fs[0].Str())
+	assert.True(t, fs[1].Time().IsZero())
+
+	m := slog.FieldsToMap(fs)
+	assert.Eq(t, "val", m["str"])
+	assert.Eq(t, slog.M{"age": int64(23)}, m["dict"])
+	assert.Eq(t, []any{time.Second, nil}, m["arr"])
+
+	// clone
+	nr := r.Clone()
+	assert.Len(t, nr.TypedFields(), 8)
+
+	// NaN is encoded as string
+	bs, err := slog.NewJSONFormatter().Format(r)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"nan":"NaN"`)
+	assert.StrContains(t, string(bs), `"zero":"0001/01/01T00:00:00.000"`)
+}
+
+func TestJSONFormatter_typedFieldsConflict(t *testing.T) {
+	tm := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+	for _, pretty := range []bool{false, true} {
+		h := newTestHandler()
+		h.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+			f.PrettyPrint = pretty
+			f.TimeFormat = time.RFC1123
+		}))
+		l := slog.NewWithHandlers(h)
+
+		l.Build(slog.InfoLevel).Str("message", "x").TimeField("time", tm).Dict("dict", func(r *slog.Record) {
+			r.TimeField("time", tm)
+		}).Msg("hello")
+
+		m := map[string]any{}
+		assert.NoErr(t, json.Unmarshal(h.Bytes(), &m))
+		assert.Eq(t, "hello", m["message"])
+		assert.Eq(t, "x", m["fields.message"])
+		assert.Eq(t, tm.Format(time.RFC1123), m["time"])
+		assert.Eq(t, map[string]any{"time": tm.Format(time.RFC1123)}, m["dict"])
+		assert.Eq(t, 1, strings.Count(h.ResetGet(), `"message"`))
+	}
+}
+
+// the output must be same as encoding/json
+func TestJSONFormatter_sameAsEncodingJSON(t *testing.T) {
+	r := newLogRecord("message <html> & \"quote\"\n\t ")
+	r.Fields = slog.M{
+		"level":   "same as level",
+		"str":     "val",
+		"int":     23,
+		"float":   1e-7,
+		"float2":  123.5,
+		"bool":    true,
+		"nil":     nil,
+		"slice":   []string{"a", "b"},
+		"uint":    uint(3),
+		"big_num": 1e21,
+	}
+	r.Extra = nil
+
+	f := slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.AddField(slog.FieldKeyTimestamp)
+		f.Aliases = slog.StringMap{"message": "msg"}
+	})
+	bs, err := f.Format(r)
+	assert.NoErr(t, err)
+
+	f.PrettyPrint = true
+	prettyBs, err := f.Format(r)
+	assert.NoErr(t, err)
+
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(prettyBs, &m))
+	want, err := json.Marshal(m)
+	assert.NoErr(t, err)
+	assert.Eq(t, string(want)+"\n", string(bs))
+	assert.Eq(t, "same as level", m["fields.level"])
+	assert.Nil(t, m["extra"])
+
+	// invalid UTF-8 is replaced, same as encoding/json
+	f.PrettyPrint = false
+	r.Message = "invalid \xff"
+	bs, err = f.Format(r)
+	assert.NoErr(t, err)
+	assert.StrContains(t, string(bs), `"msg":"invalid \ufffd"`)
+}
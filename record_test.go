@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -255,3 +256,101 @@ func TestRecord_useMultiTimes(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestRecord_inlineArgs(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewTextFormatter("{{message}} {{data}} {{ip}}\n"))
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+
+	var rec *slog.Record
+	h.beforeFormat = func(r *slog.Record) {
+		rec = r.Clone()
+	}
+
+	// logger
+	l.Info("user login", slog.NewField("ip", "127.0.0.1"), "ok", slog.NewValue("uid", 23))
+	assert.Eq(t, "user login ok", rec.Message)
+	assert.Eq(t, "127.0.0.1", rec.Fields["ip"])
+	assert.Eq(t, 23, rec.Data["uid"])
+	assert.Eq(t, "user login ok {uid:23} 127.0.0.1\n", h.ResetGet())
+
+	// format message
+	l.Warnf("user %s login", "inhere", &slog.LogField{Key: "ip", Val: "::1"})
+	assert.Eq(t, "user inhere login", rec.Message)
+	assert.Eq(t, "::1", rec.Fields["ip"])
+
+	// the args used by the verbs are not extracted
+	ipField := slog.NewField("ip", "127.0.0.1")
+	l.Infof("ip %s", ipField)
+	assert.Eq(t, fmt.Sprintf("ip %s", ipField), rec.Message)
+	assert.NotContains(t, rec.Message, "MISSING")
+	assert.Empty(t, rec.Fields)
+
+	// record and sub logger
+	l.Record().Error(slog.NewValue("key", "val"), "error message")
+	assert.Eq(t, "error message", rec.Message)
+	assert.Eq(t, slog.M{"key": "val"}, rec.Data)
+
+	sub := slog.NewSubWith(l)
+	sub.Notice("sub message", slog.NewField("ip", "10.0.0.1"))
+	assert.Eq(t, "sub message", rec.Message)
+	assert.Eq(t, "10.0.0.1", rec.Fields["ip"])
+
+	// the fields are not kept to next log
+	l.Info("message", 23)
+	assert.Eq(t, "message 23", rec.Message)
+	assert.Empty(t, rec.Fields)
+	assert.Empty(t, rec.Data)
+	h.Reset()
+
+	// package level
+	defer slog.Reset()
+	slog.Reset()
+	slog.AddHandler(h)
+	slog.Info("std message", slog.NewField("ip", "127.0.0.1"))
+	assert.Eq(t, "std message", rec.Message)
+	assert.Eq(t, "127.0.0.1", rec.Fields["ip"])
+}
+
+func TestRecord_DeepClone(t *testing.T) {
+	r := newLogRecord("message")
+	r.Fields = slog.M{"user": slog.M{"name": "inhere"}}
+	r.Data["tags"] = []string{"a", "b"}
+	r.Extra["list"] = []any{map[string]any{"k": "v"}}
+	r.Any("any", map[string]string{"k": "v"})
+	r.Caller = &runtime.Frame{Function: "main.main", Line: 23}
+
+	nr := r.DeepClone()
+	nr.Fields["user"].(slog.M)["name"] = "changed"
+	nr.Data["tags"].([]string)[0] = "changed"
+	nr.Extra["list"].([]any)[0].(map[string]any)["k"] = "changed"
+	slog.FieldsToMap(nr.TypedFields())["any"].(map[string]string)["k"] = "changed"
+	nr.Caller.Line = 24
+
+	assert.Eq(t, "inhere", r.Fields["user"].(slog.M)["name"])
+	assert.Eq(t, []string{"a", "b"}, r.Data["tags"])
+	assert.Eq(t, "v", r.Extra["list"].([]any)[0].(map[string]any)["k"])
+	assert.Eq(t, map[string]string{"k": "v"}, slog.FieldsToMap(r.TypedFields())["any"])
+	assert.Eq(t, 23, r.Caller.Line)
+}
+
+func TestRecord_Lookup(t *testing.T) {
+	r := newLogRecord("message")
+	r.Fields = slog.M{"key": "in fields"}
+	r.Int("num", 23)
+
+	v, ok := r.Lookup("key")
+	assert.True(t, ok)
+	assert.Eq(t, "in fields", v)
+	v, _ = r.Lookup("num")
+	assert.True(t, slog.ValueEqual(23, v))
+	v, _ = r.Lookup("username")
+	assert.Eq(t, "inhere", v)
+	v, _ = r.Lookup("source")
+	assert.Eq(t, "linux", v)
+
+	_, ok = r.Lookup("not-exists")
+	assert.False(t, ok)
+	assert.False(t, slog.ValueEqual(23, "23"))
+}
@@ -0,0 +1,99 @@
+package slog
+
+import (
+	"fmt"
+)
+
+// RecoverOptions options for the panic recovery helpers. see Logger.Recover, Logger.Go
+type RecoverOptions struct {
+	// Level for log the panic. default is ErrorLevel
+	//
+	// NOTE: the PanicLevel, FatalLevel will call the Logger.PanicFunc, Logger.Exit after logged.
+	Level Level
+	// Message of the log, the panic value will be appended. default is "panic recovered"
+	Message string
+	// RePanic re-panic with the value after logged, default is swallow the panic.
+	//
+	// Will run the exit handlers of the logger and global before re-panic.
+	RePanic bool
+}
+
+// RecoverFn func for config the RecoverOptions
+type RecoverFn func(o *RecoverOptions)
+
+// WithRecoverLevel set the level for log the panic
+func WithRecoverLevel(level Level) RecoverFn {
+	return func(o *RecoverOptions) { o.Level = level }
+}
+
+// WithRePanic re-panic after logged the panic
+func WithRePanic(o *RecoverOptions) { o.RePanic = true }
+
+// NewRecoverOptions create a new RecoverOptions
+func NewRecoverOptions(fns ...RecoverFn) *RecoverOptions {
+	o := &RecoverOptions{
+		Level:   ErrorLevel,
+		Message: "panic recovered",
+	}
+	for _, fn := range fns {
+		fn(o)
+	}
+	return o
+}
+
+// Recover the panic and log it with the stack. must be called by defer.
+//
+// Usage:
+//
+//	defer logger.Recover()
+//	// re-panic after logged
+//	defer logger.Recover(slog.WithRePanic)
+func (l *Logger) Recover(fns ...RecoverFn) {
+	if v := recover(); v != nil {
+		l.HandlePanic(v, fns...)
+	}
+}
+
+// Go start a goroutine to run the fn, the panic of it will be recovered and logged.
+//
+// Usage:
+//
+//	logger.Go(func() {
+//		// do something ...
+//	})
+func (l *Logger) Go(fn func(), fns ...RecoverFn) {
+	go func() {
+		defer l.Recover(fns...)
+		fn()
+	}()
+}
+
+// HandlePanic log the recovered panic value with the call stack of the panic. see Record.Stack
+// it should be called in the deferred func after recover().
+//
+// Usage:
+//
+//	defer func() {
+//		if v := recover(); v != nil {
+//			logger.HandlePanic(v)
+//		}
+//	}()
+func (l *Logger) HandlePanic(v any, fns ...RecoverFn) {
+	o := NewRecoverOptions(fns...)
+
+	if l.shouldHandle(o.Level) {
+		r := l.newRecord()
+		// skip 2 frames: the deferred func and runtime.gopanic
+		r.CallerSkip += 2
+		r.Stack = getPanicStack(l.StackDepth)
+		r.AddField(FieldKeyPanic, v)
+		r.log(o.Level, []any{o.Message + ": " + fmt.Sprint(v)})
+	}
+
+	if o.RePanic {
+		_ = l.Flush()
+		l.runExitHandlers()
+		runExitHandlers()
+		panic(v)
+	}
+}
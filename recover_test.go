@@ -0,0 +1,95 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func doPanic(msg string) { panic(msg) }
+
+func TestLogger_Recover(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+	l.CallerFlag = slog.CallerFlagFnlFcn
+
+	func() {
+		defer l.Recover()
+		doPanic("boom")
+	}()
+
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(h.Bytes(), &m))
+	h.Reset()
+	assert.Eq(t, "ERROR", m["level"])
+	assert.Eq(t, "panic recovered: boom", m["message"])
+	assert.Eq(t, "boom", m[slog.FieldKeyPanic])
+	assert.StrContains(t, m["caller"].(string), "doPanic")
+	// the stack starts from the panic site
+	frames := m[slog.FieldKeyStacktrace].([]any)
+	assert.NotEmpty(t, frames)
+	assert.Eq(t, "github.com/gookit/slog_test.doPanic", frames[0].(map[string]any)["function"])
+
+	// re-panic, run exit handlers
+	var exited bool
+	l.RegisterExitHandler(func() { exited = true })
+	assert.PanicsMsg(t, func() {
+		defer l.Recover(slog.WithRePanic, slog.WithRecoverLevel(slog.WarnLevel))
+		doPanic("boom2")
+	}, "boom2")
+	assert.True(t, exited)
+	assert.StrContains(t, h.ResetGet(), `"level":"WARNING"`)
+
+	// no panic
+	func() {
+		defer l.Recover()
+	}()
+	assert.Empty(t, h.ResetGet())
+
+	// runtime error, the stack is not in the unconfigured fields
+	h.SetFormatter(slog.NewTextFormatter("{{message}} {{stacktrace}}\n"))
+	func() {
+		defer l.Recover()
+		var m map[string]int
+		m["key"] = 1
+	}()
+	s := h.ResetGet()
+	assert.StrContains(t, s, "panic recovered: assignment to entry in nil map")
+	assert.StrContains(t, s, "slog_test.TestLogger_Recover.")
+	assert.NotContains(t, s, "runtime.gopanic")
+	assert.NotContains(t, s, slog.FieldKeyStacktrace)
+}
+
+func TestLogger_Go(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l := slog.NewWithHandlers(h)
+
+	// the error level log will flush the handlers
+	var wg sync.WaitGroup
+	wg.Add(1)
+	h.callOnFlush = wg.Done
+	l.Go(func() {
+		panic(errors.New("error in goroutine"))
+	}, func(o *slog.RecoverOptions) {
+		o.Message = "goroutine panic"
+	})
+	wg.Wait()
+	h.callOnFlush = nil
+
+	assert.StrContains(t, h.ResetGet(), "ERROR goroutine panic: error in goroutine")
+
+	// package func
+	slog.Reset()
+	slog.AddHandler(h)
+	func() {
+		defer slog.Recover()
+		doPanic("std boom")
+	}()
+	assert.StrContains(t, h.ResetGet(), "panic recovered: std boom")
+}
@@ -0,0 +1,189 @@
+package slog
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry hierarchical named loggers registry.
+//
+// The logger name is split by dot, eg: "order.payment" is a child of "order".
+// A named logger will inherit from its parents:
+//
+//   - handlers: use the handlers of the nearest logger that has handlers.
+//   - processors, GlobalFields: the parent's first, then its own.
+//   - level: the max level of the nearest logger that has set. see Logger.SetMaxLevel()
+//
+// Usage:
+//
+//	reg := slog.NewRegistry(rootLogger)
+//	l := reg.Get("order.payment")
+//	l.Info("some message") // channel: order.payment
+//
+//	// change the level of "order" and all its children at runtime
+//	reg.SetLevel("order", slog.WarnLevel)
+type Registry struct {
+	mu   sync.RWMutex
+	root *Logger
+	// loggers map by full name
+	loggers map[string]*Logger
+}
+
+// NewRegistry create a new registry with root logger
+func NewRegistry(root *Logger) *Registry {
+	return &Registry{
+		root:    root,
+		loggers: make(map[string]*Logger),
+	}
+}
+
+// Root get the root logger
+func (r *Registry) Root() *Logger { return r.root }
+
+// Get a named logger, will create it and its parents if not exists.
+//
+// Return the root logger if name is empty.
+func (r *Registry) Get(name string) *Logger {
+	name = normalizeLoggerName(name)
+	if name == "" {
+		return r.root
+	}
+
+	r.mu.RLock()
+	l, ok := r.loggers[name]
+	r.mu.RUnlock()
+	if ok {
+		return l
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.getOrCreate(name)
+}
+
+// r.mu is held.
+func (r *Registry) getOrCreate(name string) *Logger {
+	if l, ok := r.loggers[name]; ok {
+		return l
+	}
+
+	parent := r.root
+	if pos := strings.LastIndexByte(name, '.'); pos > 0 {
+		parent = r.getOrCreate(name[:pos])
+	}
+
+	l := newNamedLogger(parent, name)
+	r.loggers[name] = l
+	return l
+}
+
+// Lookup a named logger, will not create it.
+func (r *Registry) Lookup(name string) (*Logger, bool) {
+	name = normalizeLoggerName(name)
+	if name == "" {
+		return r.root, true
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	l, ok := r.loggers[name]
+	return l, ok
+}
+
+// Names get all named logger names, sorted.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.loggers))
+	for name := range r.loggers {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+
+	sort.Strings(names)
+	return names
+}
+
+// SetLevel set the max level for the named logger and its children. can be changed at runtime.
+func (r *Registry) SetLevel(name string, level Level) { r.Get(name).SetMaxLevel(level) }
+
+// ResetLevel remove the max level of the named logger, will inherit from its parent.
+func (r *Registry) ResetLevel(name string) { r.Get(name).ResetMaxLevel() }
+
+// create a named child logger, will copy some options from the parent.
+func newNamedLogger(parent *Logger, name string) *Logger {
+	return NewWithName(name, func(l *Logger) {
+		l.parent = parent
+		l.ChannelName = name
+		// copy options from parent
+		l.ReportCaller = parent.ReportCaller
+		l.CallerFlag = parent.CallerFlag
+		l.LowerLevelName = parent.LowerLevelName
+		l.BackupArgs = parent.BackupArgs
+		l.KeyValuesToFields = parent.KeyValuesToFields
+		l.StackLevel = parent.StackLevel
+		l.StackDepth = parent.StackDepth
+		l.StackSkip = parent.StackSkip
+		l.TimeClock = parent.TimeClock
+		l.ExitFunc = parent.ExitFunc
+		l.PanicFunc = parent.PanicFunc
+	})
+}
+
+// normalize name. eg: " order..payment. " -> "order.payment"
+func normalizeLoggerName(name string) string {
+	name = strings.TrimSpace(name)
+	if !strings.Contains(name, "..") && !strings.HasPrefix(name, ".") && !strings.HasSuffix(name, ".") {
+		return name
+	}
+
+	nodes := make([]string, 0, 4)
+	for _, node := range strings.Split(name, ".") {
+		if node != "" {
+			nodes = append(nodes, node)
+		}
+	}
+	return strings.Join(nodes, ".")
+}
+
+//
+// ---------------------------------------------------------------------------
+// region Named logger level
+// ---------------------------------------------------------------------------
+//
+
+// Parent get the parent logger. return nil if it is not a named child logger.
+func (l *Logger) Parent() *Logger { return l.parent }
+
+// SetMaxLevel set the max level for the logger, it will be inherited by child loggers.
+//
+// It can be safely changed at runtime. the record will be ignored if the level > max level.
+func (l *Logger) SetMaxLevel(level Level) { l.maxLevel.Store(uint32(level)) }
+
+// ResetMaxLevel remove the max level of the logger, will inherit from the parent.
+func (l *Logger) ResetMaxLevel() { l.maxLevel.Store(0) }
+
+// MaxLevel get the effective max level of the logger.
+// return TraceLevel if not set on the logger and its parents.
+func (l *Logger) MaxLevel() Level {
+	for p := l; p != nil; p = p.parent {
+		if lv := p.maxLevel.Load(); lv > 0 {
+			return Level(lv)
+		}
+	}
+	return TraceLevel
+}
+
+// IsLevelEnabled check the level is enabled by the logger max level.
+func (l *Logger) IsLevelEnabled(level Level) bool {
+	return l.MaxLevel().ShouldHandling(level)
+}
+
+// get the logger that owns the handlers. a named logger without handlers will use the parent's handlers.
+func (l *Logger) handlerOwner() *Logger {
+	o := l
+	for o.parent != nil && o.HandlersNum() == 0 {
+		o = o.parent
+	}
+	return o
+}
@@ -0,0 +1,130 @@
+package slog_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func TestRegistry_Get(t *testing.T) {
+	h := newTestHandler()
+	root := slog.NewWithHandlers(h)
+	reg := slog.NewRegistry(root)
+
+	assert.Eq(t, root, reg.Root())
+	assert.Eq(t, root, reg.Get(""))
+
+	l := reg.Get("order.payment")
+	assert.Eq(t, "order.payment", l.Name())
+	assert.Eq(t, "order.payment", l.ChannelName)
+	assert.Eq(t, reg.Get("order"), l.Parent())
+	assert.Eq(t, root, l.Parent().Parent())
+	assert.Eq(t, l, reg.Get(" order..payment. "))
+	assert.Eq(t, []string{"order", "order.payment"}, reg.Names())
+
+	_, ok := reg.Lookup("user")
+	assert.False(t, ok)
+	l2, ok := reg.Lookup("order.payment")
+	assert.True(t, ok)
+	assert.Eq(t, l, l2)
+
+	// inherit handlers
+	l.Info("payment message")
+	s := h.String()
+	assert.StrContains(t, s, "[order.payment]")
+	assert.StrContains(t, s, "payment message")
+	assert.StrContains(t, s, "registry_test.go")
+}
+
+func TestRegistry_inherit(t *testing.T) {
+	h := newTestHandler()
+	root := slog.NewWithHandlers(h)
+	root.GlobalFields = slog.M{"app": "demo", "node": "root"}
+	root.AddProcessor(slog.ProcessorFunc(func(r *slog.Record) {
+		r.AddField("trace", "root")
+	}))
+	reg := slog.NewRegistry(root)
+
+	order := reg.Get("order")
+	order.GlobalFields = slog.M{"node": "order"}
+	order.AddProcessor(slog.ProcessorFunc(func(r *slog.Record) {
+		r.AddField("trace", r.Fields["trace"].(string)+">order")
+	}))
+
+	var fields slog.M
+	h.beforeFormat = func(r *slog.Record) {
+		fields = r.Fields
+	}
+
+	reg.Get("order.payment").Info("message")
+	assert.Eq(t, "demo", fields["app"])
+	assert.Eq(t, "order", fields["node"])
+	assert.Eq(t, "root>order", fields["trace"])
+	// not changed
+	assert.Eq(t, "root", root.GlobalFields["node"])
+
+	// use own handlers
+	h2 := newTestHandler()
+	h.Reset()
+	reg.Get("order.payment").AddHandler(h2)
+	reg.Get("order.payment").Info("own handler message")
+	assert.Empty(t, h.String())
+	assert.StrContains(t, h2.String(), "own handler message")
+}
+
+func TestRegistry_level(t *testing.T) {
+	h := newTestHandler()
+	root := slog.NewWithHandlers(h)
+	reg := slog.NewRegistry(root)
+	pay := reg.Get("order.payment")
+	assert.Eq(t, slog.TraceLevel, pay.MaxLevel())
+
+	reg.SetLevel("order", slog.WarnLevel)
+	assert.Eq(t, slog.WarnLevel, pay.MaxLevel())
+	assert.False(t, pay.IsLevelEnabled(slog.InfoLevel))
+
+	pay.Info("info message")
+	pay.WithField("key", "val").Info("record info message")
+	pay.Warn("warn message")
+	root.Info("root info message")
+	s := h.String()
+	assert.Eq(t, 1, strings.Count(s, "info message"))
+	assert.NotContains(t, s, "record info message")
+	assert.StrContains(t, s, "warn message")
+	assert.StrContains(t, s, "root info message")
+
+	// override on child
+	pay.SetMaxLevel(slog.DebugLevel)
+	pay.Debug("debug message")
+	reg.Get("order").Info("order info message")
+	assert.StrContains(t, h.String(), "debug message")
+	assert.NotContains(t, h.String(), "order info message")
+
+	pay.ResetMaxLevel()
+	reg.ResetLevel("order")
+	pay.Debug("debug message2")
+	assert.StrContains(t, h.String(), "debug message2")
+}
+
+func TestGetLogger(t *testing.T) {
+	slog.Reset()
+	defer slog.Reset()
+	buf := newBuffer()
+	slog.Configure(func(l *slog.SugaredLogger) {
+		l.Output = buf
+	})
+
+	l := slog.GetLogger("order.payment")
+	assert.Eq(t, l, slog.DefaultRegistry().Get("order.payment"))
+	assert.Eq(t, slog.Std().Logger, slog.DefaultRegistry().Root())
+
+	l.Info("named logger message")
+	assert.StrContains(t, buf.String(), "[order.payment]")
+	assert.StrContains(t, buf.String(), "named logger message")
+	assert.NoErr(t, l.Flush())
+
+	slog.Reset()
+	assert.NotEq(t, l, slog.GetLogger("order.payment"))
+}
@@ -41,6 +41,9 @@ import (
 // It is directly available without any additional configuration
 var std = NewStdLogger()
 
+// default named loggers registry, the root is the std logger
+var registry = NewRegistry(std.Logger)
+
 // Std get std logger
 func Std() *SugaredLogger { return std }
 
@@ -49,8 +52,24 @@ func Reset() {
 	ResetExitHandlers(true)
 	// new std
 	std = NewStdLogger()
+	registry = NewRegistry(std.Logger)
 }
 
+// GetLogger get or create a named logger from the default registry.
+// The root of the registry is the std logger.
+//
+// Usage:
+//
+//	l := slog.GetLogger("order.payment")
+//	l.Info("some message")
+//
+//	// set level for "order" and its children at runtime
+//	slog.GetLogger("order").SetMaxLevel(slog.WarnLevel)
+func GetLogger(name string) *Logger { return registry.Get(name) }
+
+// DefaultRegistry get the default named loggers registry
+func DefaultRegistry() *Registry { return registry }
+
 // Configure the std logger
 func Configure(fn func(l *SugaredLogger)) { std.Config(fn) }
 
@@ -88,10 +107,10 @@ func FlushDaemon(onStops ...func()) { std.FlushDaemon(onStops...) }
 func StopDaemon() { std.StopDaemon() }
 
 // SetLogLevel max level for the std logger
-func SetLogLevel(l Level) { std.Level = l }
+func SetLogLevel(l Level) { std.SetLevel(l) }
 
 // SetLevelByName set max log level by name. eg: "info", "debug" ...
-func SetLevelByName(name string) { std.Level = LevelByName(name) }
+func SetLevelByName(name string) { std.SetLevel(LevelByName(name)) }
 
 // SetFormatter to std logger
 func SetFormatter(f Formatter) { std.Formatter = f }
@@ -117,6 +136,24 @@ func AddProcessor(p Processor) { std.AddProcessor(p) }
 // AddProcessors to the logger
 func AddProcessors(ps ...Processor) { std.AddProcessors(ps...) }
 
+// AddFilter to the logger
+func AddFilter(f Filter) { std.AddFilter(f) }
+
+// AddFilters to the logger
+func AddFilters(fs ...Filter) { std.AddFilters(fs...) }
+
+// -------------------------- Panic recovery -----------------------------
+
+// Recover the panic and log it by the std logger. must be called by defer. see Logger.Recover
+func Recover(fns ...RecoverFn) {
+	if v := recover(); v != nil {
+		std.HandlePanic(v, fns...)
+	}
+}
+
+// Go start a goroutine to run the fn, the panic will be logged by the std logger. see Logger.Go
+func Go(fn func(), fns ...RecoverFn) { std.Go(fn, fns...) }
+
 // -------------------------- New sub-logger -----------------------------
 
 // NewSub returns a new SubLogger on the std logger.
@@ -146,6 +183,19 @@ func WithFields(fields M) *Record { return std.WithFields(fields) }
 // WithContext new record with context
 func WithContext(ctx context.Context) *Record { return std.WithContext(ctx) }
 
+// Once new record that writes log only at the first call of the call site. see Logger.Once
+func Once(key ...string) *Record { return std.limitRecord(limitOnce, 0, key) }
+
+// Every new record that writes log at most once every the duration of the call site. see Logger.Every
+func Every(d time.Duration, key ...string) *Record {
+	return std.limitRecord(limitEvery, int64(d), key)
+}
+
+// FirstN new record that writes log only at the first n calls of the call site. see Logger.FirstN
+func FirstN(n int, key ...string) *Record {
+	return std.limitRecord(limitFirstN, int64(n), key)
+}
+
 // region Add log messages
 // -------------------------- Add log messages with level -----------------------------
 
@@ -167,6 +217,9 @@ func Trace(args ...any) { std.log(TraceLevel, args) }
 // Tracef logs a message at level TraceLevel
 func Tracef(format string, args ...any) { std.logf(TraceLevel, format, args) }
 
+// Tracew logs a message at level TraceLevel with key-values
+func Tracew(msg string, kvs ...any) { std.logw(TraceLevel, msg, kvs) }
+
 // TraceCtx logs a message at level TraceLevel with context
 func TraceCtx(ctx context.Context, args ...any) { std.logCtx(ctx, TraceLevel, args) }
 
@@ -181,6 +234,9 @@ func Debug(args ...any) { std.log(DebugLevel, args) }
 // Debugf logs a message at level DebugLevel
 func Debugf(format string, args ...any) { std.logf(DebugLevel, format, args) }
 
+// Debugw logs a message at level DebugLevel with key-values
+func Debugw(msg string, kvs ...any) { std.logw(DebugLevel, msg, kvs) }
+
 // DebugCtx logs a message at level DebugLevel with context
 func DebugCtx(ctx context.Context, args ...any) { std.logCtx(ctx, DebugLevel, args) }
 
@@ -195,6 +251,9 @@ func Info(args ...any) { std.log(InfoLevel, args) }
 // Infof logs a message at level InfoLevel
 func Infof(format string, args ...any) { std.logf(InfoLevel, format, args) }
 
+// Infow logs a message at level InfoLevel with key-values
+func Infow(msg string, kvs ...any) { std.logw(InfoLevel, msg, kvs) }
+
 // InfoCtx logs a message at level InfoLevel with context
 func InfoCtx(ctx context.Context, args ...any) { std.logCtx(ctx, InfoLevel, args) }
 
@@ -209,6 +268,9 @@ func Notice(args ...any) { std.log(NoticeLevel, args) }
 // Noticef logs a message at level NoticeLevel
 func Noticef(format string, args ...any) { std.logf(NoticeLevel, format, args) }
 
+// Noticew logs a message at level NoticeLevel with key-values
+func Noticew(msg string, kvs ...any) { std.logw(NoticeLevel, msg, kvs) }
+
 // NoticeCtx logs a message at level NoticeLevel with context
 func NoticeCtx(ctx context.Context, args ...any) { std.logCtx(ctx, NoticeLevel, args) }
 
@@ -223,6 +285,9 @@ func Warn(args ...any) { std.log(WarnLevel, args) }
 // Warnf logs a message at level WarnLevel
 func Warnf(format string, args ...any) { std.logf(WarnLevel, format, args) }
 
+// Warnw logs a message at level WarnLevel with key-values
+func Warnw(msg string, kvs ...any) { std.logw(WarnLevel, msg, kvs) }
+
 // WarnCtx logs a message at level Warn with a context
 func WarnCtx(ctx context.Context, args ...any) { std.logCtx(ctx, WarnLevel, args) }
 
@@ -237,10 +302,13 @@ func Error(args ...any) { std.log(ErrorLevel, args) }
 // Errorf logs a message at level Error
 func Errorf(format string, args ...any) { std.logf(ErrorLevel, format, args) }
 
+// Errorw logs a message at level ErrorLevel with key-values
+func Errorw(msg string, kvs ...any) { std.logw(ErrorLevel, msg, kvs) }
+
 // ErrorT logs a error type at level Error
 func ErrorT(err error) {
 	if err != nil {
-		std.log(ErrorLevel, []any{err})
+		std.logErr(ErrorLevel, err)
 	}
 }
 
@@ -252,11 +320,8 @@ func ErrorfCtx(ctx context.Context, format string, args ...any) {
 	std.logfCtx(ctx, ErrorLevel, format, args)
 }
 
-// EStack logs a error message and with call stack.
-// func EStack(args ...any) {
-// 	std.WithExtra(map[string]any{"stack": goinfo.GetCallerInfo(2)}).
-// 		log(ErrorLevel, args)
-// }
+// EStack logs an error message at level Error and with the call stack.
+func EStack(args ...any) { std.logStack(ErrorLevel, args) }
 
 // Fatal logs a message at level Fatal
 func Fatal(args ...any) { std.log(FatalLevel, args) }
@@ -264,10 +329,13 @@ func Fatal(args ...any) { std.log(FatalLevel, args) }
 // Fatalf logs a message at level Fatal
 func Fatalf(format string, args ...any) { std.logf(FatalLevel, format, args) }
 
+// Fatalw logs a message at level FatalLevel with key-values
+func Fatalw(msg string, kvs ...any) { std.logw(FatalLevel, msg, kvs) }
+
 // FatalErr logs a message at level Fatal on err is not nil
 func FatalErr(err error) {
 	if err != nil {
-		std.log(FatalLevel, []any{err})
+		std.logErr(FatalLevel, err)
 	}
 }
 
@@ -285,10 +353,13 @@ func Panic(args ...any) { std.log(PanicLevel, args) }
 // Panicf logs a message at level Panic
 func Panicf(format string, args ...any) { std.logf(PanicLevel, format, args) }
 
+// Panicw logs a message at level PanicLevel with key-values
+func Panicw(msg string, kvs ...any) { std.logw(PanicLevel, msg, kvs) }
+
 // PanicErr logs a message at level Panic on err is not nil
 func PanicErr(err error) {
 	if err != nil {
-		std.log(PanicLevel, []any{err})
+		std.logErr(PanicLevel, err)
 	}
 }
 
@@ -0,0 +1,91 @@
+package slogtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gookit/slog"
+)
+
+// AssertLogged assert has a record of the level and the message contains the substr.
+// returns the first matched record, or nil on not found.
+func (h *RecordingHandler) AssertLogged(t testing.TB, level slog.Level, substr string) *slog.Record {
+	t.Helper()
+	rs := h.Filter(func(r *slog.Record) bool {
+		return r.Level == level && strings.Contains(r.Message, substr)
+	})
+
+	if len(rs) == 0 {
+		t.Errorf("slogtest: no %s record with message contains %q, recorded messages:\n%s",
+			level.Name(), substr, h.dumpMessages())
+		return nil
+	}
+	return rs[0]
+}
+
+// AssertNotLogged assert has no record of the level and the message contains the substr.
+func (h *RecordingHandler) AssertNotLogged(t testing.TB, level slog.Level, substr string) {
+	t.Helper()
+	rs := h.Filter(func(r *slog.Record) bool {
+		return r.Level == level && strings.Contains(r.Message, substr)
+	})
+
+	if len(rs) > 0 {
+		t.Errorf("slogtest: unexpected %s record with message: %q", level.Name(), rs[0].Message)
+	}
+}
+
+// AssertCount assert the number of the recorded records
+func (h *RecordingHandler) AssertCount(t testing.TB, want int) {
+	t.Helper()
+	if n := h.Len(); n != want {
+		t.Errorf("slogtest: want %d records, but got %d, recorded messages:\n%s", want, n, h.dumpMessages())
+	}
+}
+
+// AssertLevelCount assert the number of the recorded records of the level
+func (h *RecordingHandler) AssertLevelCount(t testing.TB, level slog.Level, want int) {
+	t.Helper()
+	if n := len(h.ByLevel(level)); n != want {
+		t.Errorf("slogtest: want %d %s records, but got %d", want, level.Name(), n)
+	}
+}
+
+// AssertField assert has a record with the field key and value. see FieldValue(), ValueEqual()
+func (h *RecordingHandler) AssertField(t testing.TB, key string, want any) *slog.Record {
+	t.Helper()
+	rs := h.ByField(key, want)
+	if len(rs) == 0 {
+		t.Errorf("slogtest: no record with field %s=%v", key, want)
+		return nil
+	}
+	return rs[0]
+}
+
+// AssertRecordField assert the record has the field key and value. see FieldValue(), ValueEqual()
+func AssertRecordField(t testing.TB, r *slog.Record, key string, want any) {
+	t.Helper()
+	if r == nil {
+		t.Errorf("slogtest: the record is nil, want field %s=%v", key, want)
+		return
+	}
+
+	got, ok := FieldValue(r, key)
+	if !ok {
+		t.Errorf("slogtest: the record %q has no field %q", r.Message, key)
+	} else if !ValueEqual(got, want) {
+		t.Errorf("slogtest: the record %q field %q want %v(%T), but got %v(%T)", r.Message, key, want, want, got, got)
+	}
+}
+
+func (h *RecordingHandler) dumpMessages() string {
+	var sb strings.Builder
+	for _, r := range h.Records() {
+		sb.WriteString("  [")
+		sb.WriteString(r.Level.Name())
+		sb.WriteString("] ")
+		sb.WriteString(r.Message)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,65 @@
+package slogtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// DefaultClockStart the default start time of the Clock
+var DefaultClockStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Clock a deterministic clock for testing, can be used as slog.Logger.TimeClock
+//
+// Usage:
+//
+//	clock := slogtest.NewClock(time.Time{}, time.Second)
+//	logger.TimeClock = clock.Now
+type Clock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+// NewClock create a new Clock. if start is zero, will use DefaultClockStart.
+//
+// The step will be added to the clock after each Now() call. default is 0
+func NewClock(start time.Time, step ...time.Duration) *Clock {
+	if start.IsZero() {
+		start = DefaultClockStart
+	}
+
+	c := &Clock{now: start}
+	if len(step) > 0 {
+		c.step = step[0]
+	}
+	return c
+}
+
+// Now get the current time of the clock, and then advance the step.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now
+	c.now = c.now.Add(c.step)
+	return now
+}
+
+// ClockFn get the slog.ClockFn of the clock
+func (c *Clock) ClockFn() slog.ClockFn { return c.Now }
+
+// Advance the clock by the duration
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// Set the current time of the clock
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
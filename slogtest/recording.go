@@ -0,0 +1,169 @@
+// Package slogtest provide the helpers for testing the logs: in-memory recording handler,
+// assertions, testing.TB mirror handler and deterministic clock.
+//
+// Usage:
+//
+//	func TestSomething(t *testing.T) {
+//		l, rh := slogtest.NewLogger(t)
+//		doSomething(l)
+//
+//		rh.AssertLogged(t, slog.WarnLevel, "retry")
+//		rh.AssertField(t, "user_id", 23)
+//	}
+package slogtest
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gookit/slog"
+)
+
+// RecordingHandler a concurrency-safe handler, keep the deep copies of the handled records in memory.
+// see slog.Record.DeepClone
+type RecordingHandler struct {
+	slog.LevelFormatting
+	mu      sync.RWMutex
+	records []*slog.Record
+	// counts of the Flush and Close calls
+	flushed, closed int
+}
+
+// NewRecordingHandler create a new RecordingHandler, handle all levels by default.
+//
+//   - one level: it is the max level. eg: slog.InfoLevel will handle Info and above levels
+//   - multi levels: only handle the given levels
+func NewRecordingHandler(levels ...slog.Level) *RecordingHandler {
+	h := &RecordingHandler{}
+	switch len(levels) {
+	case 0:
+		h.SetMaxLevel(slog.TraceLevel)
+	case 1:
+		h.SetMaxLevel(levels[0])
+	default:
+		h.SetLimitLevels(levels)
+	}
+	return h
+}
+
+// Handle record, will keep a deep copy of it.
+func (h *RecordingHandler) Handle(r *slog.Record) error {
+	nr := r.DeepClone()
+
+	h.mu.Lock()
+	h.records = append(h.records, nr)
+	h.mu.Unlock()
+	return nil
+}
+
+// Flush count the flush calls
+func (h *RecordingHandler) Flush() error {
+	h.mu.Lock()
+	h.flushed++
+	h.mu.Unlock()
+	return nil
+}
+
+// Close count the close calls
+func (h *RecordingHandler) Close() error {
+	h.mu.Lock()
+	h.closed++
+	h.mu.Unlock()
+	return nil
+}
+
+// Flushed get the number of Flush calls
+func (h *RecordingHandler) Flushed() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.flushed
+}
+
+// Closed get the number of Close calls
+func (h *RecordingHandler) Closed() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.closed
+}
+
+// Reset clear the recorded records
+func (h *RecordingHandler) Reset() {
+	h.mu.Lock()
+	h.records = nil
+	h.mu.Unlock()
+}
+
+// Len get the number of recorded records
+func (h *RecordingHandler) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.records)
+}
+
+// Records get all recorded records
+func (h *RecordingHandler) Records() []*slog.Record {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]*slog.Record(nil), h.records...)
+}
+
+// Last get the last recorded record, returns nil if no records.
+func (h *RecordingHandler) Last() *slog.Record {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if n := len(h.records); n > 0 {
+		return h.records[n-1]
+	}
+	return nil
+}
+
+// Messages get the messages of all recorded records
+func (h *RecordingHandler) Messages() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	ss := make([]string, len(h.records))
+	for i, r := range h.records {
+		ss[i] = r.Message
+	}
+	return ss
+}
+
+// Filter the recorded records by the fn
+func (h *RecordingHandler) Filter(fn func(r *slog.Record) bool) []*slog.Record {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var rs []*slog.Record
+	for _, r := range h.records {
+		if fn(r) {
+			rs = append(rs, r)
+		}
+	}
+	return rs
+}
+
+// ByLevel get the records of the level
+func (h *RecordingHandler) ByLevel(level slog.Level) []*slog.Record {
+	return h.Filter(func(r *slog.Record) bool { return r.Level == level })
+}
+
+// ByMessage get the records that the message contains the substr
+func (h *RecordingHandler) ByMessage(substr string) []*slog.Record {
+	return h.Filter(func(r *slog.Record) bool { return strings.Contains(r.Message, substr) })
+}
+
+// ByField get the records that has the field key with the value. see FieldValue()
+func (h *RecordingHandler) ByField(key string, val any) []*slog.Record {
+	return h.Filter(func(r *slog.Record) bool {
+		v, ok := FieldValue(r, key)
+		return ok && ValueEqual(v, val)
+	})
+}
+
+// FieldValue get the field value from the record. alias of the slog.Record.Lookup()
+func FieldValue(r *slog.Record, key string) (any, bool) { return r.Lookup(key) }
+
+// ValueEqual check the two values is equal. alias of the slog.ValueEqual()
+func ValueEqual(a, b any) bool { return slog.ValueEqual(a, b) }
@@ -0,0 +1,136 @@
+package slogtest_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+	"github.com/gookit/slog/slogtest"
+)
+
+// fakeTB collect the errors of the assertions
+type fakeTB struct {
+	testing.TB
+	errs []string
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func TestRecordingHandler(t *testing.T) {
+	l, rh := slogtest.NewLogger(t)
+	clock := slogtest.NewClock(time.Time{}, time.Second)
+	l.TimeClock = clock.Now
+
+	l.Info("info message")
+	l.WithFields(slog.M{"user_id": 23}).Warn("warn message")
+	l.Build(slog.ErrorLevel).Int("code", 500).Str("path", "/users").Msg("error message")
+	l.Infow("info with data", "order_id", "o-001")
+	l.Panic("panic message")
+
+	assert.Eq(t, 5, rh.Len())
+	assert.Eq(t, []string{"info message", "warn message", "error message", "info with data", "panic message"}, rh.Messages())
+	assert.Eq(t, "panic message", rh.Last().Message)
+	assert.Eq(t, slogtest.DefaultClockStart, rh.Records()[0].Time)
+	assert.Eq(t, slogtest.DefaultClockStart.Add(time.Second), rh.Records()[1].Time)
+	assert.Gt(t, rh.Flushed(), 0)
+
+	assert.Len(t, rh.ByLevel(slog.InfoLevel), 2)
+	assert.Len(t, rh.ByMessage("message"), 4)
+	assert.Len(t, rh.ByField("user_id", 23), 1)
+	assert.Len(t, rh.ByField("code", 500), 1)
+	assert.Len(t, rh.ByField("order_id", "o-001"), 1)
+	assert.Empty(t, rh.ByField("user_id", 24))
+
+	// assertions
+	rh.AssertCount(t, 5)
+	rh.AssertLevelCount(t, slog.InfoLevel, 2)
+	r := rh.AssertLogged(t, slog.ErrorLevel, "error")
+	slogtest.AssertRecordField(t, r, "path", "/users")
+	rh.AssertNotLogged(t, slog.DebugLevel, "message")
+	rh.AssertField(t, "user_id", int64(23))
+
+	ft := &fakeTB{TB: t}
+	rh.AssertCount(ft, 1)
+	rh.AssertLevelCount(ft, slog.WarnLevel, 2)
+	assert.Nil(t, rh.AssertLogged(ft, slog.DebugLevel, "message"))
+	rh.AssertNotLogged(ft, slog.InfoLevel, "info")
+	assert.Nil(t, rh.AssertField(ft, "user_id", "23"))
+	slogtest.AssertRecordField(ft, r, "code", 200)
+	slogtest.AssertRecordField(ft, r, "not-exists", 200)
+	slogtest.AssertRecordField(ft, nil, "code", 200)
+	assert.Len(t, ft.errs, 8)
+	assert.StrContains(t, ft.errs[0], "want 1 records, but got 5")
+	assert.StrContains(t, ft.errs[2], "[WARNING] warn message")
+
+	// reset, close
+	rh.Reset()
+	assert.Eq(t, 0, rh.Len())
+	assert.Nil(t, rh.Last())
+	assert.NoErr(t, l.Close())
+	assert.Eq(t, 1, rh.Closed())
+}
+
+func TestRecordingHandler_concurrent(t *testing.T) {
+	rh := slogtest.NewRecordingHandler(slog.InfoLevel)
+	l := slog.NewWithHandlers(rh)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.WithData(slog.M{"i": i}).Info("message")
+			l.Debug("debug message")
+		}(i)
+	}
+	wg.Wait()
+
+	rh.AssertCount(t, 10)
+	rh.AssertField(t, "i", 9)
+
+	// the record is a copy, not changed on the logger reuse it
+	r := rh.Last()
+	l.Info("other message")
+	assert.Eq(t, "message", r.Message)
+	assert.NotEmpty(t, r.Data)
+}
+
+func TestNewRecordingHandler_levels(t *testing.T) {
+	rh := slogtest.NewRecordingHandler()
+	assert.True(t, rh.IsHandling(slog.TraceLevel))
+
+	rh = slogtest.NewRecordingHandler(slog.WarnLevel)
+	assert.True(t, rh.IsHandling(slog.ErrorLevel))
+	assert.False(t, rh.IsHandling(slog.InfoLevel))
+
+	// only handle the given levels
+	rh = slogtest.NewRecordingHandler(slog.InfoLevel, slog.DebugLevel)
+	assert.True(t, rh.IsHandling(slog.DebugLevel))
+	assert.False(t, rh.IsHandling(slog.ErrorLevel))
+
+	// keep deep copies
+	m := map[string]any{"k": "v"}
+	l := slog.NewWithHandlers(rh)
+	l.WithData(slog.M{"nested": m}).Info("message")
+	m["k"] = "changed"
+	assert.Eq(t, "v", rh.Last().Data["nested"].(map[string]any)["k"])
+}
+
+func TestClock(t *testing.T) {
+	start := time.Date(2023, 5, 6, 7, 8, 9, 0, time.UTC)
+	c := slogtest.NewClock(start)
+	assert.Eq(t, start, c.Now())
+	assert.Eq(t, start, c.ClockFn().Now())
+
+	c.Advance(time.Minute)
+	assert.Eq(t, start.Add(time.Minute), c.Now())
+	c.Set(start)
+	assert.Eq(t, start, c.Now())
+}
@@ -0,0 +1,66 @@
+package slogtest
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gookit/slog"
+)
+
+// TestingTemplate the default log template of the TestingHandler
+const TestingTemplate = "[{{level}}] {{message}} {{data}} {{extra}}"
+
+// TestingHandler mirror the logs into testing.TB.Log()
+type TestingHandler struct {
+	slog.LevelWithFormatter
+	t  testing.TB
+	mu sync.Mutex
+	// mark the test is completed, t.Log() will panic after the test completed.
+	done bool
+}
+
+// NewTestingHandler create a new TestingHandler, handle all levels by default.
+func NewTestingHandler(t testing.TB) *TestingHandler {
+	h := &TestingHandler{t: t}
+	h.Level = slog.TraceLevel
+	h.SetFormatter(slog.NewTextFormatter(TestingTemplate))
+
+	t.Cleanup(func() {
+		h.mu.Lock()
+		h.done = true
+		h.mu.Unlock()
+	})
+	return h
+}
+
+// Handle record, write to t.Log()
+func (h *TestingHandler) Handle(r *slog.Record) error {
+	bs, err := h.Formatter().Format(r)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.done {
+		h.t.Log(strings.TrimRight(string(bs), " \n"))
+	}
+	return nil
+}
+
+// Flush do nothing
+func (h *TestingHandler) Flush() error { return nil }
+
+// Close do nothing
+func (h *TestingHandler) Close() error { return nil }
+
+// NewLogger create a new logger with the RecordingHandler and TestingHandler.
+//
+// The Panic, Fatal level will do nothing on the logger. see slog.Logger.DoNothingOnPanicFatal()
+func NewLogger(t testing.TB, fns ...slog.LoggerFn) (*slog.Logger, *RecordingHandler) {
+	rh := NewRecordingHandler()
+	l := slog.NewWithHandlers(rh, NewTestingHandler(t))
+	l.DoNothingOnPanicFatal()
+	return l.Config(fns...), rh
+}
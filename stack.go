@@ -0,0 +1,111 @@
+package slog
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// DefaultStackDepth default max frames of the captured call stack. see Logger.StackDepth
+const DefaultStackDepth = 32
+
+// StackFrame a frame of the captured call stack. see Record.Stack
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// String of the frame. eg: "main.main() /path/to/main.go:23"
+func (sf StackFrame) String() string {
+	return sf.Function + "() " + sf.File + ":" + strconv.Itoa(sf.Line)
+}
+
+// the leading frames with these prefixes will be skipped on capture stack.
+var internalFramePrefixes = []string{
+	"github.com/gookit/slog.",
+	"log/slog.",
+}
+
+func isInternalFrame(fn string) bool {
+	for _, prefix := range internalFramePrefixes {
+		if strings.HasPrefix(fn, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// capture the call stack for the record on Record.EnableStack=true or the level matched Logger.StackLevel.
+func (r *Record) captureStack(l *Logger) {
+	if r.Stack != nil {
+		return
+	}
+	if !r.EnableStack && (l.StackLevel == 0 || r.Level > l.StackLevel) {
+		return
+	}
+
+	depth := l.StackDepth
+	if depth <= 0 {
+		depth = DefaultStackDepth
+	}
+	r.Stack = getCallStack(l.StackSkip, depth)
+}
+
+// get the call stack of the panic, start from the frame that calls panic().
+// it should be called in the deferred func after recover().
+func getPanicStack(depth int) []StackFrame {
+	if depth <= 0 {
+		depth = DefaultStackDepth
+	}
+
+	// more space for the deferred func frames
+	stack := getCallStack(0, depth+8)
+	for i, sf := range stack {
+		if sf.Function == "runtime.gopanic" {
+			stack = stack[i+1:]
+			break
+		}
+	}
+
+	// skip the runtime frames of the runtime error. eg: runtime.panicmem, runtime.sigpanic
+	for len(stack) > 1 && strings.HasPrefix(stack[0].Function, "runtime.") {
+		stack = stack[1:]
+	}
+	if len(stack) > depth {
+		stack = stack[:depth]
+	}
+	return stack
+}
+
+// get call stack frames, will skip the leading slog internal frames and then skip n frames.
+func getCallStack(skip, depth int) []StackFrame {
+	// more space for the internal frames
+	pcs := make([]uintptr, depth+skip+16)
+	num := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:num])
+
+	internal := true
+	stack := make([]StackFrame, 0, depth)
+	for len(stack) < depth {
+		fr, more := frames.Next()
+		if internal && isInternalFrame(fr.Function) {
+			if !more {
+				break
+			}
+			continue
+		}
+
+		internal = false
+		if skip > 0 {
+			skip--
+		} else if fr.PC != 0 {
+			stack = append(stack, StackFrame{Function: fr.Function, File: fr.File, Line: fr.Line})
+		}
+
+		if !more {
+			break
+		}
+	}
+	return stack
+}
@@ -0,0 +1,93 @@
+package slog_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+func TestLogger_StackLevel(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewJSONFormatter())
+	l := slog.NewWithHandlers(h)
+	l.DoNothingOnPanicFatal()
+	l.StackLevel = slog.ErrorLevel
+
+	var rec *slog.Record
+	h.beforeFormat = func(r *slog.Record) {
+		rec = r.Clone()
+	}
+
+	l.Warn("warn message")
+	assert.Empty(t, rec.Stack)
+	assert.NotContains(t, h.ResetGet(), slog.FieldKeyStacktrace)
+
+	l.Error("error message")
+	assert.NotEmpty(t, rec.Stack)
+	assert.Eq(t, "github.com/gookit/slog_test.TestLogger_StackLevel", rec.Stack[0].Function)
+	assert.StrContains(t, rec.Stack[0].File, "stack_test.go")
+	assert.Eq(t, rec.Caller.Line, rec.Stack[0].Line)
+
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(h.Bytes(), &m))
+	frames := m[slog.FieldKeyStacktrace].([]any)
+	assert.Len(t, frames, len(rec.Stack))
+	frame := frames[0].(map[string]any)
+	assert.Eq(t, rec.Stack[0].Function, frame["function"])
+	assert.Eq(t, rec.Stack[0].File, frame["file"])
+	assert.Eq(t, float64(rec.Stack[0].Line), frame["line"])
+	h.Reset()
+
+	// the stack is reset for the next record
+	l.Info("info message")
+	assert.Empty(t, rec.Stack)
+
+	// depth, skip
+	l.StackDepth = 1
+	l.Record().Panic("panic message")
+	assert.Len(t, rec.Stack, 1)
+	assert.Eq(t, "github.com/gookit/slog_test.TestLogger_StackLevel", rec.Stack[0].Function)
+
+	l.StackSkip = 1
+	l.Errorw("error message")
+	assert.Len(t, rec.Stack, 1)
+	assert.Eq(t, "testing.tRunner", rec.Stack[0].Function)
+	assert.StrContains(t, rec.Stack[0].String(), "testing.tRunner() ")
+}
+
+func TestLogger_EStack(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewTextFormatter("{{level}} {{message}}\n"))
+	l := slog.NewWithHandlers(h)
+
+	l.EStack("error with stack")
+	lines := strings.Split(h.ResetGet(), "\n")
+	assert.Eq(t, "ERROR error with stack", lines[0])
+	assert.Eq(t, "\tgithub.com/gookit/slog_test.TestLogger_EStack()", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "\t\t"))
+	assert.StrContains(t, lines[2], "stack_test.go:")
+	assert.Eq(t, "", lines[len(lines)-1])
+
+	// enable by record
+	h.SetFormatter(slog.NewTextFormatter("{{message}} {{stacktrace}} END\n"))
+	r := l.Record()
+	r.EnableStack = true
+	r.Info("info with stack")
+	s := h.ResetGet()
+	assert.True(t, strings.HasPrefix(s, "info with stack \n\tgithub.com/gookit/slog_test.TestLogger_EStack()\n"))
+	assert.True(t, strings.HasSuffix(s, " END\n"))
+
+	l.Info("info message")
+	assert.Eq(t, "info message  END\n", h.ResetGet())
+
+	// async mode
+	l.StackLevel = slog.WarnLevel
+	l.EnableAsync()
+	l.Warn("async warn message")
+	assert.NoErr(t, l.Flush())
+	assert.StrContains(t, h.ResetGet(), "async warn message \n\tgithub.com/gookit/slog_test.TestLogger_EStack()\n")
+	assert.NoErr(t, l.Close())
+}
@@ -0,0 +1,219 @@
+//go:build go1.21
+
+package slog
+
+import (
+	"context"
+	stdslog "log/slog"
+	"runtime"
+)
+
+// LevelFromStdlib convert the Go standard library log/slog level to Level.
+//
+// NOTE: never map to FatalLevel, PanicLevel, the std levels above error are mapped to ErrorLevel.
+func LevelFromStdlib(lv stdslog.Level) Level {
+	switch {
+	case lv >= stdslog.LevelError:
+		return ErrorLevel
+	case lv >= stdslog.LevelWarn:
+		return WarnLevel
+	case lv > stdslog.LevelInfo:
+		return NoticeLevel
+	case lv >= stdslog.LevelInfo:
+		return InfoLevel
+	case lv >= stdslog.LevelDebug:
+		return DebugLevel
+	default:
+		return TraceLevel
+	}
+}
+
+// LevelToStdlib convert the Level to Go standard library log/slog level.
+func LevelToStdlib(lv Level) stdslog.Level {
+	switch {
+	case lv <= ErrorLevel: // panic, fatal, error
+		return stdslog.LevelError + stdslog.Level(ErrorLevel-lv)/100
+	case lv <= WarnLevel:
+		return stdslog.LevelWarn
+	case lv <= NoticeLevel:
+		return stdslog.LevelInfo + 2
+	case lv <= InfoLevel:
+		return stdslog.LevelInfo
+	case lv <= DebugLevel:
+		return stdslog.LevelDebug
+	default:
+		return stdslog.LevelDebug - 4
+	}
+}
+
+// StdlibHandlerOption for the StdlibHandler
+type StdlibHandlerOption struct {
+	// UseFields add the attrs to Record.Fields. default is added to Record.Data
+	UseFields bool
+}
+
+// StdlibHandler an adapter implements the Go standard library log/slog.Handler,
+// will write the records by a Logger.
+//
+//   - levels are mapped by LevelFromStdlib()
+//   - attrs and groups are added to Record.Data, groups are nested M.
+//   - the source position is set to Record.Caller
+//
+// Usage:
+//
+//	l := slog.NewWithHandlers(h1, h2)
+//	sl := stdslog.New(slog.NewStdlibHandler(l))
+//	sl.Info("some message", "key", "value")
+type StdlibHandler struct {
+	logger *Logger
+	opt    StdlibHandlerOption
+	// groups and attrs from WithGroup(), WithAttrs()
+	goas []stdlibGroupOrAttrs
+}
+
+type stdlibGroupOrAttrs struct {
+	group string
+	attrs []stdslog.Attr
+}
+
+// NewStdlibHandler create a new StdlibHandler
+func NewStdlibHandler(l *Logger, fns ...func(opt *StdlibHandlerOption)) *StdlibHandler {
+	h := &StdlibHandler{logger: l}
+	for _, fn := range fns {
+		fn(&h.opt)
+	}
+	return h
+}
+
+// StdlibLogger create a new Go standard library log/slog.Logger, which writes records by the logger.
+func (l *Logger) StdlibLogger(fns ...func(opt *StdlibHandlerOption)) *stdslog.Logger {
+	return stdslog.New(NewStdlibHandler(l, fns...))
+}
+
+// Logger get the logger of the handler
+func (h *StdlibHandler) Logger() *Logger { return h.logger }
+
+// Enabled implements the log/slog.Handler
+func (h *StdlibHandler) Enabled(_ context.Context, level stdslog.Level) bool {
+	return h.logger.shouldHandle(LevelFromStdlib(level))
+}
+
+// Handle implements the log/slog.Handler
+func (h *StdlibHandler) Handle(ctx context.Context, sr stdslog.Record) error {
+	level := LevelFromStdlib(sr.Level)
+	r := h.logger.newRecord()
+	r.Ctx = ctx
+	r.Level = level
+	r.Time = sr.Time
+	r.Message = sr.Message
+
+	// the source position
+	r.callerSet = true
+	if sr.PC != 0 {
+		fs := runtime.CallersFrames([]uintptr{sr.PC})
+		f, _ := fs.Next()
+		r.Caller = &f
+	}
+
+	if attrs := h.buildAttrs(sr); len(attrs) > 0 {
+		if h.opt.UseFields {
+			r.AddFields(attrs)
+		} else {
+			r.Data = attrs
+		}
+	}
+
+	h.logger.writeRecord(level, r)
+	h.logger.releaseRecord(r)
+	return nil
+}
+
+func (h *StdlibHandler) buildAttrs(sr stdslog.Record) M {
+	goas := h.goas
+	// the groups without attrs will be ignored
+	if sr.NumAttrs() == 0 {
+		for len(goas) > 0 && goas[len(goas)-1].group != "" {
+			goas = goas[:len(goas)-1]
+		}
+	}
+	if len(goas) == 0 && sr.NumAttrs() == 0 {
+		return nil
+	}
+
+	attrs := make(M, sr.NumAttrs())
+	cur := attrs
+	for _, goa := range goas {
+		if goa.group != "" {
+			sub := make(M)
+			cur[goa.group] = sub
+			cur = sub
+			continue
+		}
+
+		for _, a := range goa.attrs {
+			addStdlibAttr(cur, a)
+		}
+	}
+
+	sr.Attrs(func(a stdslog.Attr) bool {
+		addStdlibAttr(cur, a)
+		return true
+	})
+	return attrs
+}
+
+// WithAttrs implements the log/slog.Handler
+func (h *StdlibHandler) WithAttrs(attrs []stdslog.Attr) stdslog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(stdlibGroupOrAttrs{attrs: attrs})
+}
+
+// WithGroup implements the log/slog.Handler
+func (h *StdlibHandler) WithGroup(name string) stdslog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(stdlibGroupOrAttrs{group: name})
+}
+
+func (h *StdlibHandler) withGroupOrAttrs(goa stdlibGroupOrAttrs) *StdlibHandler {
+	nh := *h
+	nh.goas = make([]stdlibGroupOrAttrs, len(h.goas)+1)
+	copy(nh.goas, h.goas)
+	nh.goas[len(h.goas)] = goa
+	return &nh
+}
+
+func addStdlibAttr(m M, a stdslog.Attr) {
+	a.Value = a.Value.Resolve()
+	// ignore empty attr
+	if a.Equal(stdslog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() != stdslog.KindGroup {
+		m[a.Key] = a.Value.Any()
+		return
+	}
+
+	attrs := a.Value.Group()
+	if len(attrs) == 0 {
+		return
+	}
+
+	// inline the group attrs on key is empty
+	if a.Key == "" {
+		for _, ga := range attrs {
+			addStdlibAttr(m, ga)
+		}
+		return
+	}
+
+	sub := make(M, len(attrs))
+	for _, ga := range attrs {
+		addStdlibAttr(sub, ga)
+	}
+	m[a.Key] = sub
+}
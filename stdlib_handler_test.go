@@ -0,0 +1,134 @@
+//go:build go1.21
+
+package slog_test
+
+import (
+	"context"
+	stdslog "log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"testing/slogtest"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+// mapHandler collect records as maps, like the JSON output of std log/slog
+type mapHandler struct {
+	slog.LevelFormatting
+	mu      sync.Mutex
+	records []map[string]any
+}
+
+func (h *mapHandler) Handle(r *slog.Record) error {
+	m := make(map[string]any, len(r.Data)+3)
+	if !r.Time.IsZero() {
+		m[stdslog.TimeKey] = r.Time
+	}
+	m[stdslog.LevelKey] = r.LevelName()
+	m[stdslog.MessageKey] = r.Message
+	for k, v := range r.Data {
+		m[k] = toAnyMap(v)
+	}
+
+	h.mu.Lock()
+	h.records = append(h.records, m)
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *mapHandler) Flush() error { return nil }
+func (h *mapHandler) Close() error { return nil }
+
+func toAnyMap(v any) any {
+	if m, ok := v.(slog.M); ok {
+		am := make(map[string]any, len(m))
+		for k, val := range m {
+			am[k] = toAnyMap(val)
+		}
+		return am
+	}
+	return v
+}
+
+func TestStdlibHandler_slogtest(t *testing.T) {
+	h := &mapHandler{LevelFormatting: *slog.NewMaxLevelFormatting(slog.TraceLevel)}
+	l := slog.NewWithHandlers(h)
+	// the std Record.Time is zero will be ignored
+	l.TimeClock = func() time.Time { return time.Time{} }
+
+	err := slogtest.TestHandler(slog.NewStdlibHandler(l), func() []map[string]any {
+		return h.records
+	})
+	assert.NoErr(t, err)
+}
+
+func TestStdlibHandler(t *testing.T) {
+	th := newTestHandler()
+	l := slog.NewWithHandlers(th)
+	l.DoNothingOnPanicFatal()
+
+	var rec *slog.Record
+	th.beforeFormat = func(r *slog.Record) {
+		rec = r.Clone()
+	}
+
+	sl := l.StdlibLogger()
+	sl.With("app", "demo").WithGroup("req").Warn("std warn message", "id", 23)
+
+	assert.NotNil(t, rec)
+	assert.Eq(t, slog.WarnLevel, rec.Level)
+	assert.Eq(t, "std warn message", rec.Message)
+	assert.Eq(t, "demo", rec.Data["app"])
+	assert.Eq(t, slog.M{"id": int64(23)}, rec.Data["req"])
+	assert.NotNil(t, rec.Caller)
+	assert.StrContains(t, rec.Caller.File, "stdlib_handler_test.go")
+	assert.StrContains(t, th.String(), "std warn message")
+
+	// use fields
+	sl = stdslog.New(slog.NewStdlibHandler(l, func(opt *slog.StdlibHandlerOption) {
+		opt.UseFields = true
+	}))
+	sl.Error("std error message", "key", "val")
+	assert.Eq(t, slog.ErrorLevel, rec.Level)
+	assert.Eq(t, "val", rec.Fields["key"])
+	assert.Empty(t, rec.Data)
+
+	// level
+	h := slog.NewStdlibHandler(slog.NewWithHandlers(newTestHandler()))
+	assert.NotNil(t, h.Logger())
+	h.Logger().SetMaxLevel(slog.InfoLevel)
+	assert.True(t, h.Enabled(context.Background(), stdslog.LevelInfo))
+	assert.False(t, h.Enabled(context.Background(), stdslog.LevelDebug))
+}
+
+func TestLevelFromStdlib(t *testing.T) {
+	tests := []struct {
+		std stdslog.Level
+		lv  slog.Level
+	}{
+		{stdslog.LevelError + 4, slog.ErrorLevel},
+		{stdslog.LevelError, slog.ErrorLevel},
+		{stdslog.LevelWarn, slog.WarnLevel},
+		{stdslog.LevelInfo + 2, slog.NoticeLevel},
+		{stdslog.LevelInfo, slog.InfoLevel},
+		{stdslog.LevelDebug, slog.DebugLevel},
+		{stdslog.LevelDebug - 4, slog.TraceLevel},
+	}
+
+	for _, tt := range tests {
+		assert.Eq(t, tt.lv, slog.LevelFromStdlib(tt.std))
+	}
+
+	for _, lv := range slog.AllLevels {
+		std := slog.LevelToStdlib(lv)
+		if lv <= slog.ErrorLevel {
+			assert.True(t, std >= stdslog.LevelError)
+		} else {
+			assert.Eq(t, lv, slog.LevelFromStdlib(std), lv.String())
+		}
+	}
+	assert.True(t, strings.HasPrefix(slog.LevelToStdlib(slog.PanicLevel).String(), "ERROR+"))
+}
@@ -3,6 +3,7 @@ package slog
 import (
 	"io"
 	"os"
+	"sync"
 
 	"github.com/gookit/color"
 )
@@ -21,7 +22,14 @@ type SugaredLogger struct {
 	// Level for log handling. if log record level <= Level, it will be record. default: DebugLevel
 	//
 	// TIP: setting the level to lower will ignore more logs.
+	//
+	// NOTE: the changes in Config() will be synced to the LevelVar, and SetLevel() updates both.
+	// please use SetLevel() to change the level at runtime.
 	Level Level
+	// LevelVar for change the level at runtime safely. it will be created from Level by default.
+	LevelVar *LevelVar
+	// lock for sync the Level and LevelVar
+	lvMu sync.Mutex
 }
 
 // NewStd logger instance, alias of NewStdLogger()
@@ -80,21 +88,64 @@ func (sl *SugaredLogger) Config(fns ...SugaredLoggerFn) *SugaredLogger {
 	for _, fn := range fns {
 		fn(sl)
 	}
+
+	// sync the Level to the LevelVar, it may be changed by fns
+	sl.lvMu.Lock()
+	if sl.LevelVar == nil {
+		sl.LevelVar = NewLevelVar(sl.Level)
+	} else {
+		sl.LevelVar.Set(sl.Level)
+	}
+	sl.lvMu.Unlock()
 	return sl
 }
 
 // Reset the logger
 func (sl *SugaredLogger) Reset() {
 	sl.Level = DebugLevel
+	sl.LevelVar = NewLevelVar(DebugLevel)
 	sl.Output = os.Stdout
 	sl.Formatter = NewTextFormatter()
 }
 
 // IsHandling Check if the current level can be handling
 func (sl *SugaredLogger) IsHandling(level Level) bool {
+	if sl.LevelVar != nil {
+		return sl.LevelVar.ShouldHandling(level)
+	}
 	return sl.Level.ShouldHandling(level)
 }
 
+// SetLevel set the log level. it is safe for call at runtime.
+func (sl *SugaredLogger) SetLevel(level Level) {
+	sl.lvMu.Lock()
+	defer sl.lvMu.Unlock()
+
+	sl.Level = level
+	if sl.LevelVar != nil {
+		sl.LevelVar.Set(level)
+	}
+}
+
+// SetLevelVar set the LevelVar for change the level at runtime safely. the Level will be synced from it.
+func (sl *SugaredLogger) SetLevelVar(lv *LevelVar) {
+	sl.lvMu.Lock()
+	defer sl.lvMu.Unlock()
+
+	sl.LevelVar = lv
+	if lv != nil {
+		sl.Level = lv.Level()
+	}
+}
+
+// GetLevel get the current log level
+func (sl *SugaredLogger) GetLevel() Level {
+	if sl.LevelVar != nil {
+		return sl.LevelVar.Level()
+	}
+	return sl.Level
+}
+
 // Handle log record
 func (sl *SugaredLogger) Handle(record *Record) error {
 	bts, err := sl.Formatter.Format(record)
@@ -112,12 +163,14 @@ func (sl *SugaredLogger) Handle(record *Record) error {
 //
 //	if enable async/buffer mode, please call the Close() before exit.
 func (sl *SugaredLogger) Close() error {
+	sl.closeAsync()
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
 	_ = sl.Logger.VisitAll(func(handler Handler) error {
 		// TIP: must exclude self, because self is a handler
 		if _, ok := handler.(*SugaredLogger); !ok {
-			if err := handler.Close(); err != nil {
-				sl.err = err
-			}
+			sl.callHandler(OpClose, handler, nil)
 		}
 		return nil
 	})
@@ -128,12 +181,9 @@ func (sl *SugaredLogger) Close() error {
 // Flush all logs. alias of the FlushAll()
 func (sl *SugaredLogger) Flush() error { return sl.FlushAll() }
 
-// FlushAll all logs
+// FlushAll all logs. the errors will be reported to the ErrorHandler.
 func (sl *SugaredLogger) FlushAll() error {
-	return sl.Logger.VisitAll(func(handler Handler) error {
-		if _, ok := handler.(*SugaredLogger); !ok {
-			_ = handler.Flush()
-		}
-		return nil
-	})
+	sl.drainAsync()
+	// TIP: the self handler is skipped in flushAll()
+	return sl.lockAndFlushAll()
 }
@@ -0,0 +1,167 @@
+package slog
+
+import (
+	"context"
+	"errors"
+)
+
+// TraceParentHeader the W3C trace context header name. see https://www.w3.org/TR/trace-context/
+const TraceParentHeader = "traceparent"
+
+// TraceParentKey the context key of the W3C traceparent value. used by the W3CTraceExtractor
+var TraceParentKey = NewContextKey[string](TraceParentHeader)
+
+// ErrInvalidTraceParent error for invalid W3C traceparent value
+var ErrInvalidTraceParent = errors.New("slog: invalid W3C traceparent value")
+
+// TraceContext the trace info for correlate logs with distributed traces.
+type TraceContext struct {
+	// TraceID 32 lowercase hex chars. eg: "4bf92f3577b34da6a3ce929d0e0e4736"
+	TraceID string
+	// SpanID 16 lowercase hex chars. eg: "00f067aa0ba902b7"
+	SpanID string
+	// Flags 2 lowercase hex chars. eg: "01" is sampled
+	Flags string
+}
+
+// IsValid check the trace id and span id is not empty
+func (tc TraceContext) IsValid() bool { return tc.TraceID != "" && tc.SpanID != "" }
+
+// Sampled check the sampled flag is set
+func (tc TraceContext) Sampled() bool {
+	return len(tc.Flags) == 2 && fromHex(tc.Flags[1])&1 == 1
+}
+
+// TraceExtractor extract the trace context from context.Context. eg: from OpenTelemetry span
+type TraceExtractor interface {
+	ExtractTrace(ctx context.Context) (TraceContext, bool)
+}
+
+// TraceExtractorFunc func type, implements the TraceExtractor
+type TraceExtractorFunc func(ctx context.Context) (TraceContext, bool)
+
+// ExtractTrace implements the TraceExtractor
+func (fn TraceExtractorFunc) ExtractTrace(ctx context.Context) (TraceContext, bool) {
+	return fn(ctx)
+}
+
+// W3CTraceExtractor the built-in extractor, parse the W3C traceparent value saved in the context by TraceParentKey.
+var W3CTraceExtractor TraceExtractor = TraceExtractorFunc(func(ctx context.Context) (TraceContext, bool) {
+	s, ok := TraceParentKey.Value(ctx)
+	if !ok {
+		return TraceContext{}, false
+	}
+
+	tc, err := ParseTraceParent(s)
+	return tc, err == nil
+})
+
+// TraceProcessor add the trace info to Record.Fields by the extractor. default use W3CTraceExtractor
+//
+// The field keys are FieldKeyTraceID, FieldKeySpanID, FieldKeyTraceFlags.
+// Can be renamed by JSONFormatter.Aliases. eg: {"trace_id": "traceId"}
+//
+// Usage:
+//
+//	logger.AddProcessor(slog.TraceProcessor())
+//
+//	// in the HTTP handler
+//	ctx := slog.ContextWithTraceHeader(r.Context(), r.Header)
+//	logger.WithContext(ctx).Info("some message")
+func TraceProcessor(extractor ...TraceExtractor) Processor {
+	ex := W3CTraceExtractor
+	if len(extractor) > 0 && extractor[0] != nil {
+		ex = extractor[0]
+	}
+
+	return ProcessorFunc(func(r *Record) {
+		if r.Ctx == nil {
+			return
+		}
+
+		tc, ok := ex.ExtractTrace(r.Ctx)
+		if !ok || !tc.IsValid() {
+			return
+		}
+
+		if r.Fields == nil {
+			r.Fields = make(M, 3)
+		}
+		r.Fields[FieldKeyTraceID] = tc.TraceID
+		r.Fields[FieldKeySpanID] = tc.SpanID
+		if tc.Flags != "" {
+			r.Fields[FieldKeyTraceFlags] = tc.Flags
+		}
+	})
+}
+
+// ContextWithTraceParent returns a new context with the W3C traceparent value.
+func ContextWithTraceParent(ctx context.Context, traceparent string) context.Context {
+	return TraceParentKey.WithValue(ctx, traceparent)
+}
+
+// ContextWithTraceHeader returns a new context with the incoming W3C traceparent header.
+// if the header is not exists, will return the ctx.
+//
+// Usage:
+//
+//	ctx := slog.ContextWithTraceHeader(r.Context(), r.Header)
+func ContextWithTraceHeader(ctx context.Context, header interface{ Get(key string) string }) context.Context {
+	if s := header.Get(TraceParentHeader); s != "" {
+		return TraceParentKey.WithValue(ctx, s)
+	}
+	return ctx
+}
+
+// ParseTraceParent parse the W3C traceparent value. format: "version-traceid-spanid-flags"
+//
+// eg: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+func ParseTraceParent(s string) (tc TraceContext, err error) {
+	// version 00 is fixed 55 chars, the future versions may be longer.
+	if len(s) < 55 || (len(s) > 55 && s[55] != '-') {
+		return tc, ErrInvalidTraceParent
+	}
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return tc, ErrInvalidTraceParent
+	}
+
+	version := s[0:2]
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(s) != 55) {
+		return tc, ErrInvalidTraceParent
+	}
+
+	tc.TraceID, tc.SpanID, tc.Flags = s[3:35], s[36:52], s[53:55]
+	if !isLowerHex(tc.TraceID) || isAllZero(tc.TraceID) {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+	if !isLowerHex(tc.SpanID) || isAllZero(tc.SpanID) || !isLowerHex(tc.Flags) {
+		return TraceContext{}, ErrInvalidTraceParent
+	}
+	return tc, nil
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAllZero(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' {
+			return false
+		}
+	}
+	return true
+}
+
+func fromHex(c byte) byte {
+	if c >= 'a' {
+		return c - 'a' + 10
+	}
+	return c - '0'
+}
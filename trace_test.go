@@ -0,0 +1,83 @@
+package slog_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+	"github.com/gookit/slog"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tc, err := slog.ParseTraceParent(testTraceParent)
+	assert.NoErr(t, err)
+	assert.Eq(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.Eq(t, "00f067aa0ba902b7", tc.SpanID)
+	assert.Eq(t, "01", tc.Flags)
+	assert.True(t, tc.IsValid())
+	assert.True(t, tc.Sampled())
+
+	// future version with more parts
+	tc, err = slog.ParseTraceParent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-what")
+	assert.NoErr(t, err)
+	assert.False(t, tc.Sampled())
+
+	for _, s := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	} {
+		_, err = slog.ParseTraceParent(s)
+		assert.ErrIs(t, err, slog.ErrInvalidTraceParent, "input: %q", s)
+	}
+}
+
+func TestTraceProcessor(t *testing.T) {
+	h := newTestHandler()
+	h.SetFormatter(slog.NewJSONFormatter(func(f *slog.JSONFormatter) {
+		f.Aliases = slog.StringMap{slog.FieldKeyTraceID: "traceId"}
+	}))
+	l := slog.NewWithHandlers(h)
+	l.AddProcessor(slog.TraceProcessor())
+
+	header := http.Header{}
+	header.Set("Traceparent", testTraceParent)
+	ctx := slog.ContextWithTraceHeader(context.Background(), header)
+
+	l.WithContext(ctx).Info("info message")
+	m := map[string]any{}
+	assert.NoErr(t, json.Unmarshal(h.Bytes(), &m))
+	h.Reset()
+	assert.Eq(t, "4bf92f3577b34da6a3ce929d0e0e4736", m["traceId"])
+	assert.Eq(t, "00f067aa0ba902b7", m[slog.FieldKeySpanID])
+	assert.Eq(t, "01", m[slog.FieldKeyTraceFlags])
+	assert.NotContains(t, m, slog.FieldKeyTraceID)
+
+	// no trace info
+	l.WithContext(context.Background()).Info("info message")
+	l.Info("info message")
+	ctx = slog.ContextWithTraceParent(context.Background(), "invalid")
+	l.WithContext(ctx).Info("info message")
+	assert.NotContains(t, h.ResetGet(), slog.FieldKeySpanID)
+
+	// custom extractor
+	l = slog.NewWithHandlers(h)
+	l.AddProcessor(slog.TraceProcessor(slog.TraceExtractorFunc(func(ctx context.Context) (slog.TraceContext, bool) {
+		return slog.TraceContext{TraceID: "trace1", SpanID: "span1"}, true
+	})))
+	l.WithContext(context.Background()).Info("info message")
+	s := h.ResetGet()
+	assert.StrContains(t, s, `"traceId":"trace1"`)
+	assert.StrContains(t, s, `"span_id":"span1"`)
+	assert.NotContains(t, s, slog.FieldKeyTraceFlags)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strconv"
 	"strings"
@@ -13,16 +14,6 @@ import (
 	"github.com/valyala/bytebufferpool"
 )
 
-// const (
-// 	defaultMaxCallerDepth  int = 15
-// 	defaultKnownSlogFrames int = 4
-// )
-
-// Stack that attempts to recover the data for all goroutines.
-// func getCallStacks(callerSkip int) []byte {
-// 	return nil
-// }
-
 // FormatLevelName Format the level name, specify the length returned,
 // fill the space with less length, and truncate than the length
 func FormatLevelName(name string, length int) string {
@@ -120,6 +111,34 @@ func formatArgsWithSpaces(vs []any) string {
 	// return byteutil.String(bb.B) // perf: Reduce one memory allocation
 }
 
+// countFormatArgs count the args used by the verbs of the printf format.
+// returns false if the format has an explicit argument index. eg: %[1]d
+func countFormatArgs(format string) (n int, ok bool) {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		// flags, width and precision
+		for i++; i < len(format); i++ {
+			c := format[i]
+			if c == '*' {
+				n++
+			} else if c == '[' {
+				return 0, false
+			} else if !strings.ContainsRune("+-# 0123456789.", rune(c)) {
+				break
+			}
+		}
+
+		// the verb, "%%" does not use an arg
+		if i < len(format) && format[i] != '%' {
+			n++
+		}
+	}
+	return n, true
+}
+
 // EncodeToString data to string
 func EncodeToString(v any) string {
 	switch mp := v.(type) {
@@ -180,3 +199,27 @@ func parseTemplateToFields(tplStr string) []string {
 func printStderr(args ...any) {
 	_, _ = fmt.Fprintln(os.Stderr, args...)
 }
+
+// ValueEqual check the two values is equal. the numbers will be compared by value, eg: int(1) == int64(1)
+func ValueEqual(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	fa, ok1 := toFloat64(a)
+	fb, ok2 := toFloat64(b)
+	return ok1 && ok2 && fa == fb
+}
+
+func toFloat64(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
@@ -74,3 +74,25 @@ func TestUtil_formatArgsWithSpaces(t *testing.T) {
 
 	assert.NotEmpty(t, formatArgsWithSpaces([]any{timex.Now().T()}))
 }
+
+func TestUtil_countFormatArgs(t *testing.T) {
+	tests := []struct {
+		format string
+		n      int
+		ok     bool
+	}{
+		{"message", 0, true},
+		{"ip %s", 1, true},
+		{"100%% %d", 1, true},
+		{"%-10s|%+.2f|%#v", 3, true},
+		{"%*d %.*f", 4, true},
+		{"%[1]s %[1]s", 0, false},
+		{"end %", 0, true},
+	}
+
+	for _, tt := range tests {
+		n, ok := countFormatArgs(tt.format)
+		assert.Eq(t, tt.n, n, tt.format)
+		assert.Eq(t, tt.ok, ok, tt.format)
+	}
+}